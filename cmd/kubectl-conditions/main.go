@@ -0,0 +1,146 @@
+// Command kubectl-conditions is a kubectl plugin that prints conditions of a
+// Cluster and its related objects as a tree: the infrastructure and control
+// plane objects referenced from the Cluster, and the Cluster MachinePools
+// and MachineDeployments with their infrastructure objects.
+//
+// Objects are read from manifests, so the plugin works offline, e.g. with
+// objects from a support bundle or from kubectl get output:
+//
+//    kubectl get clusters,azureclusters,kubeadmcontrolplanes,machinepools,azuremachinepools -n org-test -o yaml | kubectl conditions
+//    kubectl conditions -f cluster.yaml -f node-pools.yaml test
+//
+// When the cluster name is not specified, trees for all clusters found in
+// manifests are printed. Output is colored only when it is written to a
+// terminal and colors are not disabled with -no-color flag or NO_COLOR
+// environment variable.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/microerror"
+)
+
+const usage = `Print conditions of a Cluster and its related objects as a tree.
+
+Usage:
+  kubectl conditions [flags] [CLUSTER_NAME]
+
+Flags:
+`
+
+// fileFlags is a flag that can be specified multiple times.
+type fileFlags []string
+
+func (f *fileFlags) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *fileFlags) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// isTerminal returns true when the specified writer is a terminal. It is a
+// variable, so that it can be replaced in tests.
+var isTerminal = func(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer, now time.Time) error {
+	var files fileFlags
+	var namespace string
+	var noColor bool
+
+	flags := flag.NewFlagSet("kubectl-conditions", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+	flags.Var(&files, "f", `Manifest file to read objects from, can be specified multiple times. Use "-" or omit to read from stdin.`)
+	flags.StringVar(&namespace, "n", "", "Namespace of the cluster. Clusters from all namespaces are printed by default.")
+	flags.BoolVar(&noColor, "no-color", os.Getenv("NO_COLOR") != "", "Disable colored output. Defaults to true when NO_COLOR environment variable is set.")
+
+	err := flags.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		// Usage is already printed.
+		return nil
+	} else if err != nil {
+		return microerror.Maskf(invalidFlagError, "%s", err.Error())
+	}
+	if flags.NArg() > 1 {
+		return microerror.Maskf(invalidFlagError, "expected at most one cluster name, got %d arguments", flags.NArg())
+	}
+	if len(files) == 0 {
+		files = fileFlags{stdinPath}
+	}
+
+	objects, err := readManifestFiles(files, stdin)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	clusters := selectClusters(objects, namespace, flags.Arg(0))
+	if len(clusters) == 0 {
+		return microerror.Maskf(clusterNotFoundError, "no cluster found in manifests")
+	}
+
+	for i, cluster := range clusters {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+
+		err = renderTree(stdout, buildTree(cluster, objects), renderOptions{now: now, color: !noColor && isTerminal(stdout)})
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// selectClusters returns clusters with the specified namespace and name. An
+// empty namespace or name matches all clusters.
+func selectClusters(objects manifests, namespace, name string) manifests {
+	var result manifests
+	for _, object := range objects {
+		if object.GetKind() != kindCluster {
+			continue
+		}
+		if namespace != "" && object.GetNamespace() != namespace {
+			continue
+		}
+		if name != "" && object.GetName() != name {
+			continue
+		}
+
+		result = append(result, object)
+	}
+
+	return result
+}
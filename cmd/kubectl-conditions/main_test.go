@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/microerror"
+)
+
+func TestRun(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		args           []string
+		stdin          string
+		errorMatcher   func(error) bool
+		expectedOutput string
+	}{
+		{
+			name: "case 0: Tree for cluster is printed from file",
+			args: []string{"-no-color", "-f", "testdata/cluster.yaml", "test"},
+			expectedOutput: `Cluster org-test/test
+├── Ready              False  Warning  NodePoolsNotReady        5m
+├── Creating           False           CreationCompleted        120m
+├── NodePoolsReady     False  Warning  NodePoolsNotReady        5m
+├── AzureCluster org-test/test
+│   └── Ready          True                                     2d
+├── KubeadmControlPlane org-test/test-control-plane (not found)
+├── MachinePool org-test/np1
+│   ├── Ready          False  Warning  WaitingForReplicasReady  5m
+│   ├── ReplicasReady  False  Warning  WaitingForReplicasReady  5m
+│   └── AzureMachinePool org-test/np1
+│       └── Ready      True                                     60m
+└── MachineDeployment org-test/md1
+    ├── Ready          True                                     60m
+    └── AzureMachineTemplate org-test/md1
+        └── No conditions
+`,
+		},
+		{
+			name:  "case 1: Trees for all clusters in namespace are printed from stdin",
+			args:  []string{"-no-color", "-n", "org-other"},
+			stdin: mustReadFile(t, "testdata/cluster.yaml"),
+			expectedOutput: `Cluster org-other/other
+└── No conditions
+`,
+		},
+		{
+			name:         "case 2: Error is returned when cluster is not found",
+			args:         []string{"-no-color", "-f", "testdata/cluster.yaml", "missing"},
+			errorMatcher: IsClusterNotFound,
+		},
+		{
+			name:         "case 3: Error is returned for multiple cluster names",
+			args:         []string{"-f", "testdata/cluster.yaml", "test", "other"},
+			errorMatcher: IsInvalidFlag,
+		},
+		{
+			name:         "case 4: Error is returned for unknown flag",
+			args:         []string{"-o", "yaml"},
+			errorMatcher: IsInvalidFlag,
+		},
+		{
+			name:         "case 5: Error is returned for invalid manifest",
+			args:         []string{"-f", "-"},
+			stdin:        "metadata:\n  name: test\n",
+			errorMatcher: IsInvalidManifest,
+		},
+		{
+			name: "case 6: Usage is printed without error for help flag",
+			args: []string{"-h"},
+		},
+		{
+			name:  "case 7: Output is not colored when it is not written to terminal",
+			args:  []string{"-n", "org-other"},
+			stdin: mustReadFile(t, "testdata/cluster.yaml"),
+			expectedOutput: `Cluster org-other/other
+└── No conditions
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+			var stdout, stderr bytes.Buffer
+
+			err := run(tc.args, strings.NewReader(tc.stdin), &stdout, &stderr, now)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(microerror.Cause(err)):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if stdout.String() != tc.expectedOutput {
+				t.Fatalf("expected output:\n%s\ngot:\n%s", tc.expectedOutput, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRunWithColor(t *testing.T) {
+	if os.Getenv("NO_COLOR") != "" {
+		t.Skip("colors are disabled with NO_COLOR environment variable")
+	}
+	defer func(original func(w io.Writer) bool) { isTerminal = original }(isTerminal)
+	isTerminal = func(w io.Writer) bool { return true }
+	var stdout, stderr bytes.Buffer
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	err := run([]string{"-f", "testdata/cluster.yaml", "test"}, strings.NewReader(""), &stdout, &stderr, now)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	lines := strings.Split(stdout.String(), "\n")
+	expected := "├── Ready              " + colorYellow + "False" + colorReset + "  Warning  NodePoolsNotReady        " + colorGray + "5m" + colorReset
+	if lines[1] != expected {
+		t.Fatalf("expected line %q, got %q", expected, lines[1])
+	}
+	expected = "├── Creating           " + colorGreen + "False" + colorReset + "           CreationCompleted        " + colorGray + "120m" + colorReset
+	if lines[2] != expected {
+		t.Fatalf("expected line %q, got %q", expected, lines[2])
+	}
+}
+
+func mustReadFile(t *testing.T, path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	return string(content)
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/giantswarm/microerror"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const stdinPath = "-"
+
+// manifests contains all objects read from manifests, in the order in which
+// they were read.
+type manifests []*unstructured.Unstructured
+
+// readManifestFiles reads objects from the specified files. Path "-" reads
+// from stdin.
+func readManifestFiles(paths []string, stdin io.Reader) (manifests, error) {
+	var result manifests
+	for _, path := range paths {
+		var objects manifests
+		var err error
+		if path == stdinPath {
+			objects, err = readManifests(stdin)
+		} else {
+			objects, err = readManifestFile(path)
+		}
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		result = append(result, objects...)
+	}
+
+	return result, nil
+}
+
+func readManifestFile(path string) (manifests, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	defer f.Close()
+
+	objects, err := readManifests(f)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return objects, nil
+}
+
+// readManifests reads all objects from a YAML or JSON stream. YAML streams
+// can contain multiple documents, and lists (e.g. output of kubectl get -o
+// yaml) are flattened. Empty documents are skipped.
+func readManifests(r io.Reader) (manifests, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(r, 4096)
+
+	var result manifests
+	for {
+		var content map[string]interface{}
+		err := decoder.Decode(&content)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, microerror.Maskf(invalidManifestError, "%s", err.Error())
+		}
+		if len(content) == 0 {
+			continue
+		}
+
+		object := &unstructured.Unstructured{Object: content}
+		if object.GetKind() == "" {
+			return nil, microerror.Maskf(invalidManifestError, "object %#q does not have kind", object.GetName())
+		}
+
+		if object.IsList() {
+			list, err := object.ToList()
+			if err != nil {
+				return nil, microerror.Maskf(invalidManifestError, "%s", err.Error())
+			}
+			for i := range list.Items {
+				result = append(result, &list.Items[i])
+			}
+			continue
+		}
+
+		result = append(result, object)
+	}
+
+	return result, nil
+}
+
+// find returns the object with the specified kind, namespace and name, or
+// nil when there is no such object.
+func (m manifests) find(kind, namespace, name string) *unstructured.Unstructured {
+	for _, object := range m {
+		if object.GetKind() == kind && object.GetNamespace() == namespace && object.GetName() == name {
+			return object
+		}
+	}
+
+	return nil
+}
+
+// ofCluster returns objects of the specified kind that belong to the
+// specified cluster, i.e. objects in the same namespace whose
+// spec.clusterName or cluster name label is set to the cluster name.
+func (m manifests) ofCluster(kind string, cluster *unstructured.Unstructured) manifests {
+	var result manifests
+	for _, object := range m {
+		if object.GetKind() != kind || object.GetNamespace() != cluster.GetNamespace() {
+			continue
+		}
+
+		clusterName, _, _ := unstructured.NestedString(object.Object, "spec", "clusterName")
+		if clusterName == "" {
+			clusterName = object.GetLabels()[capi.ClusterLabelName]
+		}
+		if clusterName == cluster.GetName() {
+			result = append(result, object)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadManifests(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         string
+		errorMatcher  func(error) bool
+		expectedNames []string
+	}{
+		{
+			name:          "case 0: Multiple YAML documents are read and empty documents are skipped",
+			input:         "kind: Cluster\nmetadata:\n  name: a\n---\n---\nkind: MachinePool\nmetadata:\n  name: b\n",
+			expectedNames: []string{"Cluster/a", "MachinePool/b"},
+		},
+		{
+			name:          "case 1: Lists are flattened",
+			input:         "kind: List\nitems:\n- kind: Cluster\n  metadata:\n    name: a\n- kind: AzureCluster\n  metadata:\n    name: a\n",
+			expectedNames: []string{"Cluster/a", "AzureCluster/a"},
+		},
+		{
+			name:          "case 2: JSON is read",
+			input:         `{"kind": "Cluster", "metadata": {"name": "a"}}`,
+			expectedNames: []string{"Cluster/a"},
+		},
+		{
+			name:         "case 3: Error is returned for object without kind",
+			input:        "metadata:\n  name: a\n",
+			errorMatcher: IsInvalidManifest,
+		},
+		{
+			name:         "case 4: Error is returned for invalid YAML",
+			input:        "kind: [Cluster\n",
+			errorMatcher: IsInvalidManifest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			objects, err := readManifests(strings.NewReader(tc.input))
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			var names []string
+			for _, object := range objects {
+				names = append(names, object.GetKind()+"/"+object.GetName())
+			}
+			if strings.Join(names, ",") != strings.Join(tc.expectedNames, ",") {
+				t.Fatalf("expected objects %v, got %v", tc.expectedNames, names)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/duration"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+
+	"github.com/giantswarm/conditions/pkg/conditions"
+)
+
+const (
+	kindCluster           = "Cluster"
+	kindMachinePool       = "MachinePool"
+	kindMachineDeployment = "MachineDeployment"
+)
+
+// displayedConditions contains conditions that are printed for every object
+// in the tree, in the order in which they are printed. Conditions that are
+// not set on an object are not printed.
+var displayedConditions = []capi.ConditionType{
+	capi.ReadyCondition,
+	conditions.Creating,
+	conditions.Upgrading,
+	conditions.InfrastructureReady,
+	conditions.ControlPlaneReady,
+	conditions.NodePoolsReady,
+	capiexp.ReplicasReadyCondition,
+}
+
+// inProgressConditions contains conditions for which status True means that
+// an operation is in progress and status False means that it is completed.
+var inProgressConditions = map[capi.ConditionType]bool{
+	conditions.Creating:  true,
+	conditions.Upgrading: true,
+}
+
+const (
+	colorReset  = "\033[0m"
+	colorBold   = "\033[1m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorCyan   = "\033[36m"
+	colorGray   = "\033[90m"
+)
+
+// treeNode is an object in the tree. Object is nil when the object is
+// referenced, but it was not found in manifests.
+type treeNode struct {
+	kind      string
+	namespace string
+	name      string
+	object    *unstructured.Unstructured
+	children  []*treeNode
+}
+
+// buildTree returns the tree for the specified cluster: the cluster with
+// its infrastructure and control plane objects, and its MachinePools and
+// MachineDeployments with their infrastructure objects, i.e. objects
+// referenced from Spec.Template.Spec.InfrastructureRef.
+func buildTree(cluster *unstructured.Unstructured, objects manifests) *treeNode {
+	root := objectNode(cluster)
+	root.appendRef(objects, cluster, "spec", "infrastructureRef")
+	root.appendRef(objects, cluster, "spec", "controlPlaneRef")
+
+	for _, machinePool := range objects.ofCluster(kindMachinePool, cluster) {
+		node := objectNode(machinePool)
+		node.appendRef(objects, machinePool, "spec", "template", "spec", "infrastructureRef")
+		root.children = append(root.children, node)
+	}
+	for _, machineDeployment := range objects.ofCluster(kindMachineDeployment, cluster) {
+		node := objectNode(machineDeployment)
+		node.appendRef(objects, machineDeployment, "spec", "template", "spec", "infrastructureRef")
+		root.children = append(root.children, node)
+	}
+
+	return root
+}
+
+func objectNode(object *unstructured.Unstructured) *treeNode {
+	return &treeNode{
+		kind:      object.GetKind(),
+		namespace: object.GetNamespace(),
+		name:      object.GetName(),
+		object:    object,
+	}
+}
+
+// appendRef appends the object referenced from the specified field of the
+// owner object, if the reference is set. The referenced object is appended
+// also when it is not found in manifests, so that missing objects are
+// visible in the tree.
+func (n *treeNode) appendRef(objects manifests, owner *unstructured.Unstructured, fields ...string) {
+	ref, found, _ := unstructured.NestedMap(owner.Object, fields...)
+	if !found {
+		return
+	}
+
+	kind, _, _ := unstructured.NestedString(ref, "kind")
+	name, _, _ := unstructured.NestedString(ref, "name")
+	namespace, _, _ := unstructured.NestedString(ref, "namespace")
+	if namespace == "" {
+		namespace = owner.GetNamespace()
+	}
+	if kind == "" || name == "" {
+		return
+	}
+
+	n.children = append(n.children, &treeNode{
+		kind:      kind,
+		namespace: namespace,
+		name:      name,
+		object:    objects.find(kind, namespace, name),
+	})
+}
+
+type renderOptions struct {
+	now   time.Time
+	color bool
+}
+
+// treeCell is a single printed value, with the color used when colors are
+// enabled.
+type treeCell struct {
+	text  string
+	color string
+}
+
+// treeRow is a printed line. Object lines have a single cell, condition
+// lines have a cell for every column, which are aligned across all
+// condition lines.
+type treeRow struct {
+	prefix string
+	cells  []treeCell
+}
+
+// renderTree prints the tree to the specified writer.
+func renderTree(w io.Writer, root *treeNode, options renderOptions) error {
+	var rows []treeRow
+	root.collectRows(&rows, "", "", options.now)
+
+	widths := map[int]int{}
+	for _, row := range rows {
+		if len(row.cells) == 1 {
+			continue
+		}
+		for i, cell := range row.cells {
+			width := utf8.RuneCountInString(cell.text)
+			if i == 0 {
+				width += utf8.RuneCountInString(row.prefix)
+			}
+			if width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	for _, row := range rows {
+		var b strings.Builder
+		b.WriteString(row.prefix)
+		for i, cell := range row.cells {
+			if options.color && cell.color != "" && cell.text != "" {
+				b.WriteString(cell.color + cell.text + colorReset)
+			} else {
+				b.WriteString(cell.text)
+			}
+
+			// Pad all columns except the last one, so that colors do not
+			// affect the alignment.
+			if len(row.cells) > 1 && i < len(row.cells)-1 {
+				width := widths[i] - utf8.RuneCountInString(cell.text)
+				if i == 0 {
+					width -= utf8.RuneCountInString(row.prefix)
+				}
+				b.WriteString(strings.Repeat(" ", width+2))
+			}
+		}
+
+		_, err := fmt.Fprintln(w, strings.TrimRight(b.String(), " "))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+func (n *treeNode) collectRows(rows *[]treeRow, prefix, childPrefix string, now time.Time) {
+	title := fmt.Sprintf("%s %s/%s", n.kind, n.namespace, n.name)
+	if n.object == nil {
+		*rows = append(*rows, treeRow{prefix: prefix, cells: []treeCell{{text: title + " (not found)", color: colorRed}}})
+		return
+	}
+	*rows = append(*rows, treeRow{prefix: prefix, cells: []treeCell{{text: title, color: colorBold}}})
+
+	var children []func(prefix, childPrefix string)
+	object := conditions.NewUnstructuredObject(n.object)
+	for _, conditionType := range displayedConditions {
+		condition := capiconditions.Get(object, conditionType)
+		if condition == nil {
+			continue
+		}
+		children = append(children, func(prefix, _ string) {
+			*rows = append(*rows, treeRow{prefix: prefix, cells: conditionCells(condition, now)})
+		})
+	}
+	if len(children) == 0 {
+		children = append(children, func(prefix, _ string) {
+			*rows = append(*rows, treeRow{prefix: prefix, cells: []treeCell{{text: "No conditions", color: colorGray}}})
+		})
+	}
+	for _, child := range n.children {
+		child := child
+		children = append(children, func(prefix, childPrefix string) {
+			child.collectRows(rows, prefix, childPrefix, now)
+		})
+	}
+
+	for i, child := range children {
+		if i == len(children)-1 {
+			child(childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			child(childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
+func conditionCells(condition *capi.Condition, now time.Time) []treeCell {
+	age := "-"
+	if !condition.LastTransitionTime.IsZero() {
+		age = duration.HumanDuration(now.Sub(condition.LastTransitionTime.Time))
+	}
+
+	return []treeCell{
+		{text: string(condition.Type)},
+		{text: string(condition.Status), color: statusColor(condition)},
+		{text: string(condition.Severity)},
+		{text: condition.Reason},
+		{text: age, color: colorGray},
+	}
+}
+
+// statusColor returns the color of the condition status. Status True is
+// green, except for Creating and Upgrading, for which False is green, and
+// failing conditions are colored by their severity.
+func statusColor(condition *capi.Condition) string {
+	switch condition.Status {
+	case corev1.ConditionTrue:
+		if inProgressConditions[condition.Type] {
+			return colorYellow
+		}
+		return colorGreen
+	case corev1.ConditionFalse:
+		if inProgressConditions[condition.Type] {
+			return colorGreen
+		}
+		switch condition.Severity {
+		case capi.ConditionSeverityInfo:
+			return colorCyan
+		case capi.ConditionSeverityWarning:
+			return colorYellow
+		default:
+			return colorRed
+		}
+	default:
+		return colorGray
+	}
+}
@@ -0,0 +1,130 @@
+package conditions
+
+import (
+	"regexp"
+	"time"
+
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// All returns a CheckOption that checks if all specified check options are
+// successful. When no check options are specified, it is always successful.
+//
+// Example:
+//
+//    IsReadyFalse(cluster, All(WithReason("Provisioning"), WithSeverityWarning()))
+//
+func All(checkOptions ...CheckOption) CheckOption {
+	return func(condition *capi.Condition) bool {
+		for _, checkOption := range checkOptions {
+			if !checkOption(condition) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Any returns a CheckOption that checks if at least one of the specified
+// check options is successful. When no check options are specified, it is
+// never successful.
+//
+// Example:
+//
+//    IsUpgradingFalse(cluster, Any(WithUpgradeCompletedReason(), WithUpgradeNotStartedReason()))
+//
+func Any(checkOptions ...CheckOption) CheckOption {
+	return func(condition *capi.Condition) bool {
+		for _, checkOption := range checkOptions {
+			if checkOption(condition) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Not returns a CheckOption that checks if the specified check option is not
+// successful. Like all other check options, it is not successful when the
+// condition is nil.
+//
+// Example:
+//
+//    IsCreatingFalse(cluster, Not(WithExistingObjectReason()))
+//
+func Not(checkOption CheckOption) CheckOption {
+	return func(condition *capi.Condition) bool {
+		return condition != nil && !checkOption(condition)
+	}
+}
+
+// WithReasonIn returns a CheckOption that checks if condition reason is set
+// to one of the specified values.
+func WithReasonIn(reasons ...string) CheckOption {
+	return func(condition *capi.Condition) bool {
+		if condition == nil {
+			return false
+		}
+
+		for _, reason := range reasons {
+			if condition.Reason == reason {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// WithMinSeverity returns a CheckOption that checks if condition severity is
+// at least the specified severity, where Error is more severe than Warning,
+// Warning is more severe than Info, and Info is more severe than no
+// severity.
+func WithMinSeverity(severity capi.ConditionSeverity) CheckOption {
+	return func(condition *capi.Condition) bool {
+		return condition != nil && severityRank(condition.Severity) >= severityRank(severity)
+	}
+}
+
+// WithMessageMatching returns a CheckOption that checks if condition message
+// matches the specified regular expression.
+func WithMessageMatching(pattern *regexp.Regexp) CheckOption {
+	return func(condition *capi.Condition) bool {
+		return condition != nil && pattern.MatchString(condition.Message)
+	}
+}
+
+// WithLastTransitionBefore returns a CheckOption that checks if condition
+// LastTransitionTime is before the specified time.
+func WithLastTransitionBefore(t time.Time) CheckOption {
+	return func(condition *capi.Condition) bool {
+		return condition != nil && condition.LastTransitionTime.Time.Before(t)
+	}
+}
+
+// WithLastTransitionAfter returns a CheckOption that checks if condition
+// LastTransitionTime is after the specified time.
+func WithLastTransitionAfter(t time.Time) CheckOption {
+	return func(condition *capi.Condition) bool {
+		return condition != nil && condition.LastTransitionTime.Time.After(t)
+	}
+}
+
+// WithLastTransitionOlderThan returns a CheckOption that checks if condition
+// LastTransitionTime is older than the specified duration, i.e. if the
+// condition has been in its current state for longer than the specified
+// duration. Current time is taken from the clock set with WithClock when the
+// check is done.
+//
+// Example:
+//
+//    IsReadyFalse(cluster, WithLastTransitionOlderThan(10*time.Minute))
+//
+func WithLastTransitionOlderThan(d time.Duration, options ...TimeOption) CheckOption {
+	clock := clockOf(options)
+	return func(condition *capi.Condition) bool {
+		return condition != nil && clock.Now().Sub(condition.LastTransitionTime.Time) > d
+	}
+}
@@ -0,0 +1,176 @@
+package conditions
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestCheckOptionCombinators(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	condition := &capi.Condition{
+		Type:               capi.ReadyCondition,
+		Status:             corev1.ConditionFalse,
+		Reason:             "Provisioning",
+		Severity:           capi.ConditionSeverityWarning,
+		Message:            "Waiting for 2 of 3 nodes",
+		LastTransitionTime: metav1.NewTime(now.Add(-15 * time.Minute)),
+	}
+
+	testCases := []struct {
+		name           string
+		checkOption    CheckOption
+		condition      *capi.Condition
+		expectedOutput bool
+	}{
+		{
+			name:           "case 0: All returns true when all check options are successful",
+			checkOption:    All(WithReason("Provisioning"), WithSeverityWarning()),
+			condition:      condition,
+			expectedOutput: true,
+		},
+		{
+			name:           "case 1: All returns false when one check option fails",
+			checkOption:    All(WithReason("Provisioning"), WithSeverityError()),
+			condition:      condition,
+			expectedOutput: false,
+		},
+		{
+			name:           "case 2: All returns true for no check options",
+			checkOption:    All(),
+			condition:      condition,
+			expectedOutput: true,
+		},
+		{
+			name:           "case 3: Any returns true when one check option is successful",
+			checkOption:    Any(WithReason("Deleting"), WithSeverityWarning()),
+			condition:      condition,
+			expectedOutput: true,
+		},
+		{
+			name:           "case 4: Any returns false for no check options",
+			checkOption:    Any(),
+			condition:      condition,
+			expectedOutput: false,
+		},
+		{
+			name:           "case 5: Not returns true when check option fails",
+			checkOption:    Not(WithReason("Deleting")),
+			condition:      condition,
+			expectedOutput: true,
+		},
+		{
+			name:           "case 6: Not returns false for nil condition",
+			checkOption:    Not(WithReason("Deleting")),
+			condition:      nil,
+			expectedOutput: false,
+		},
+		{
+			name:           "case 7: WithReasonIn returns true when reason is in the set",
+			checkOption:    WithReasonIn("Deleting", "Provisioning"),
+			condition:      condition,
+			expectedOutput: true,
+		},
+		{
+			name:           "case 8: WithReasonIn returns false when reason is not in the set",
+			checkOption:    WithReasonIn("Deleting", "Scaling"),
+			condition:      condition,
+			expectedOutput: false,
+		},
+		{
+			name:           "case 9: WithMinSeverity returns true for equal severity",
+			checkOption:    WithMinSeverity(capi.ConditionSeverityWarning),
+			condition:      condition,
+			expectedOutput: true,
+		},
+		{
+			name:           "case 10: WithMinSeverity returns true for lower minimal severity",
+			checkOption:    WithMinSeverity(capi.ConditionSeverityInfo),
+			condition:      condition,
+			expectedOutput: true,
+		},
+		{
+			name:           "case 11: WithMinSeverity returns false for higher minimal severity",
+			checkOption:    WithMinSeverity(capi.ConditionSeverityError),
+			condition:      condition,
+			expectedOutput: false,
+		},
+		{
+			name:           "case 12: WithMessageMatching returns true when message matches",
+			checkOption:    WithMessageMatching(regexp.MustCompile(`\d of \d nodes`)),
+			condition:      condition,
+			expectedOutput: true,
+		},
+		{
+			name:           "case 13: WithMessageMatching returns false when message does not match",
+			checkOption:    WithMessageMatching(regexp.MustCompile(`^All good$`)),
+			condition:      condition,
+			expectedOutput: false,
+		},
+		{
+			name:           "case 14: WithLastTransitionBefore returns true for later time",
+			checkOption:    WithLastTransitionBefore(now),
+			condition:      condition,
+			expectedOutput: true,
+		},
+		{
+			name:           "case 15: WithLastTransitionAfter returns false for later time",
+			checkOption:    WithLastTransitionAfter(now),
+			condition:      condition,
+			expectedOutput: false,
+		},
+		{
+			name:           "case 16: WithLastTransitionOlderThan returns true for shorter duration",
+			checkOption:    WithLastTransitionOlderThan(10*time.Minute, WithClock(NewFakeClock(now))),
+			condition:      condition,
+			expectedOutput: true,
+		},
+		{
+			name:           "case 17: WithLastTransitionOlderThan returns false for longer duration",
+			checkOption:    WithLastTransitionOlderThan(20*time.Minute, WithClock(NewFakeClock(now))),
+			condition:      condition,
+			expectedOutput: false,
+		},
+		{
+			name:           "case 18: WithLastTransitionOlderThan returns false for nil condition",
+			checkOption:    WithLastTransitionOlderThan(time.Minute, WithClock(NewFakeClock(now))),
+			condition:      nil,
+			expectedOutput: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			result := tc.checkOption(tc.condition)
+			if result != tc.expectedOutput {
+				t.Logf("expected %t, got %t for %s", tc.expectedOutput, result, sprintCondition(tc.condition))
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestCheckOptionCombinatorsWithIsFalse(t *testing.T) {
+	cluster := &capi.Cluster{
+		Status: capi.ClusterStatus{
+			Conditions: capi.Conditions{
+				{
+					Type:     Upgrading,
+					Status:   corev1.ConditionFalse,
+					Reason:   UpgradePendingReason,
+					Severity: capi.ConditionSeverityInfo,
+				},
+			},
+		},
+	}
+
+	if !IsUpgradingFalse(cluster, Any(WithUpgradePendingReason(), WithUpgradeNotStartedReason()), Not(WithMinSeverity(capi.ConditionSeverityWarning))) {
+		t.Fatalf("expected IsUpgradingFalse to return true for %s", sprintConditionForObject(cluster, Upgrading))
+	}
+}
@@ -0,0 +1,98 @@
+package conditions
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides current time to functions that depend on it, so that the
+// time can be controlled, e.g. in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock is a Clock that returns the actual current time.
+type RealClock struct{}
+
+// Now returns the current local time.
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// FakeClock is a Clock that returns a fixed time, which is changed only with
+// Set and Step. It is safe for concurrent use.
+//
+// Example:
+//
+//    clock := conditions.NewFakeClock(time.Now())
+//    conditions.EscalateSeverity(cluster, conditions.InfrastructureReady, conditions.WithClock(clock))
+//    clock.Step(10 * time.Minute)
+//    conditions.EscalateSeverity(cluster, conditions.InfrastructureReady, conditions.WithClock(clock))
+//
+type FakeClock struct {
+	mutex sync.RWMutex
+	now   time.Time
+}
+
+// NewFakeClock returns a new FakeClock that is set to the specified time.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{
+		now: now,
+	}
+}
+
+// Now returns the time to which the clock is set.
+func (c *FakeClock) Now() time.Time {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.now
+}
+
+// Set sets the clock to the specified time.
+func (c *FakeClock) Set(now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = now
+}
+
+// Step moves the clock by the specified duration.
+func (c *FakeClock) Step(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+}
+
+type timeOptions struct {
+	clock Clock
+}
+
+// TimeOption is an option for functions that depend on the current time. It
+// can also be used as HistoryOption, ReadySummaryOption and FlappingOption.
+type TimeOption func(options *timeOptions)
+
+// WithClock returns a TimeOption that makes a function take the current time
+// from the specified clock. Default is RealClock.
+func WithClock(clock Clock) TimeOption {
+	return func(options *timeOptions) {
+		options.clock = clock
+	}
+}
+
+// clockOf returns the clock set with the specified options, or RealClock.
+func clockOf(options []TimeOption) Clock {
+	timeOpts := timeOptions{}
+	for _, option := range options {
+		option(&timeOpts)
+	}
+
+	return timeOpts.clockOrDefault()
+}
+
+// clockOrDefault returns the clock set with options, or RealClock.
+func (o timeOptions) clockOrDefault() Clock {
+	if o.clock == nil {
+		return RealClock{}
+	}
+
+	return o.clock
+}
@@ -0,0 +1,66 @@
+package conditions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(now)
+
+	if !clock.Now().Equal(now) {
+		t.Fatalf("expected %s, got %s", now, clock.Now())
+	}
+
+	clock.Step(10 * time.Minute)
+	if !clock.Now().Equal(now.Add(10 * time.Minute)) {
+		t.Fatalf("expected %s, got %s", now.Add(10*time.Minute), clock.Now())
+	}
+
+	clock.Set(now)
+	if !clock.Now().Equal(now) {
+		t.Fatalf("expected %s, got %s", now, clock.Now())
+	}
+}
+
+func TestClockOf(t *testing.T) {
+	testCases := []struct {
+		name          string
+		options       []TimeOption
+		expectedClock Clock
+	}{
+		{
+			name:          "case 0: RealClock is used by default",
+			expectedClock: RealClock{},
+		},
+		{
+			name:          "case 1: RealClock is used when nil clock is set",
+			options:       []TimeOption{WithClock(nil)},
+			expectedClock: RealClock{},
+		},
+		{
+			name:          "case 2: Clock set with WithClock is used",
+			options:       []TimeOption{WithClock(NewFakeClock(time.Time{}))},
+			expectedClock: NewFakeClock(time.Time{}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			clock := clockOf(tc.options)
+			switch tc.expectedClock.(type) {
+			case RealClock:
+				if _, ok := clock.(RealClock); !ok {
+					t.Fatalf("expected RealClock, got %T", clock)
+				}
+			case *FakeClock:
+				if _, ok := clock.(*FakeClock); !ok {
+					t.Fatalf("expected *FakeClock, got %T", clock)
+				}
+			}
+		})
+	}
+}
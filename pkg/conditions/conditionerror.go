@@ -0,0 +1,181 @@
+package conditions
+
+import (
+	"fmt"
+
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// ConditionError is returned when a condition is not in the expected state.
+// It wraps UnexpectedConditionStatusError or UnsupportedConditionStatusError,
+// so it can be checked with IsUnexpectedConditionStatus and
+// IsUnsupportedConditionStatus, while its fields can be read with
+// errors.As.
+//
+// Example:
+//
+//    err := conditions.EnsureTrue(cluster, conditions.ControlPlaneReady)
+//    var conditionErr *conditions.ConditionError
+//    if errors.As(err, &conditionErr) {
+//        logger.Debugf(ctx, "%s %s is not ready: %s", conditionErr.Kind, conditionErr.Name, conditionErr.ActualReason)
+//    }
+//
+type ConditionError struct {
+	// Kind, Namespace and Name identify the object on which the condition
+	// was checked.
+	Kind      string
+	Namespace string
+	Name      string
+
+	// ConditionType is the type of the checked condition.
+	ConditionType capi.ConditionType
+
+	// ExpectedStatus and ExpectedReason describe the expected condition
+	// state. ExpectedReason is empty when any reason is expected.
+	ExpectedStatus corev1.ConditionStatus
+	ExpectedReason string
+
+	// Found tells if the condition is set on the object. ActualStatus and
+	// ActualReason are empty when it is not.
+	Found        bool
+	ActualStatus corev1.ConditionStatus
+	ActualReason string
+
+	err error
+}
+
+// NewUnexpectedConditionStatusError returns a ConditionError that wraps
+// UnexpectedConditionStatusError, for the condition of the specified type
+// that was expected to have the specified status and reason. Empty reason
+// means any reason. It does not panic when the object is nil.
+func NewUnexpectedConditionStatusError(object Object, conditionType capi.ConditionType, expectedStatus corev1.ConditionStatus, expectedReason string) error {
+	conditionErr := newConditionError(object, conditionType)
+	conditionErr.ExpectedStatus = expectedStatus
+	conditionErr.ExpectedReason = expectedReason
+	conditionErr.err = microerror.Maskf(UnexpectedConditionStatusError, "%s", conditionErr.message())
+
+	return conditionErr
+}
+
+// NewUnsupportedConditionStatusError returns a ConditionError that wraps
+// UnsupportedConditionStatusError, for the condition of the specified type
+// that has a status other than True, False or Unknown. It does not panic
+// when the object is nil.
+func NewUnsupportedConditionStatusError(object Object, conditionType capi.ConditionType) error {
+	conditionErr := newConditionError(object, conditionType)
+	conditionErr.err = microerror.Maskf(UnsupportedConditionStatusError, "%s", conditionErr.message())
+
+	return conditionErr
+}
+
+// Error returns the error message.
+func (e *ConditionError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying UnexpectedConditionStatusError or
+// UnsupportedConditionStatusError.
+func (e *ConditionError) Unwrap() error {
+	return e.err
+}
+
+func newConditionError(object Object, conditionType capi.ConditionType) *ConditionError {
+	conditionErr := &ConditionError{
+		ConditionType: conditionType,
+	}
+	if isNilGetter(object) {
+		return conditionErr
+	}
+
+	conditionErr.Kind = KindOf(object)
+	conditionErr.Namespace = object.GetNamespace()
+	conditionErr.Name = object.GetName()
+
+	condition := capiconditions.Get(object, conditionType)
+	if condition != nil {
+		conditionErr.Found = true
+		conditionErr.ActualStatus = condition.Status
+		conditionErr.ActualReason = condition.Reason
+	}
+
+	return conditionErr
+}
+
+func (e *ConditionError) message() string {
+	object := fmt.Sprintf("%s %s/%s", e.Kind, e.Namespace, e.Name)
+	if e.Kind == "" && e.Name == "" {
+		object = "nil object"
+	}
+
+	actual := conditionNotSet
+	if e.Found {
+		actual = fmt.Sprintf("status %s", e.ActualStatus)
+		if e.ActualReason != "" {
+			actual += fmt.Sprintf(" and reason %s", e.ActualReason)
+		}
+	}
+
+	if e.ExpectedStatus == "" {
+		return fmt.Sprintf("Unsupported status for condition %s on %s, got %s", e.ConditionType, object, actual)
+	}
+
+	expected := fmt.Sprintf("status %s", e.ExpectedStatus)
+	if e.ExpectedReason != "" {
+		expected += fmt.Sprintf(" and reason %s", e.ExpectedReason)
+	}
+
+	return fmt.Sprintf("Expected that condition %s on %s has %s, but got %s", e.ConditionType, object, expected, actual)
+}
+
+// EnsureTrue returns nil if the condition of the specified type is set on
+// the object with status True. Otherwise it returns ConditionError. It does
+// not panic when the object is nil or when the condition is not set.
+func EnsureTrue(object Object, conditionType capi.ConditionType) error {
+	return ensureStatus(object, conditionType, corev1.ConditionTrue, "", IsTrue)
+}
+
+// EnsureFalse returns nil if the condition of the specified type is set on
+// the object with status False and all optionally specified checks are
+// successful. Otherwise it returns ConditionError. It does not panic when
+// the object is nil or when the condition is not set.
+func EnsureFalse(object Object, conditionType capi.ConditionType, checkOptions ...CheckOption) error {
+	return ensureStatus(object, conditionType, corev1.ConditionFalse, "", func(condition *capi.Condition) bool {
+		return IsFalse(condition, checkOptions...)
+	})
+}
+
+// EnsureFalseWithReason returns nil if the condition of the specified type
+// is set on the object with status False and the specified reason.
+// Otherwise it returns ConditionError. It does not panic when the object is
+// nil or when the condition is not set.
+func EnsureFalseWithReason(object Object, conditionType capi.ConditionType, reason string) error {
+	return ensureStatus(object, conditionType, corev1.ConditionFalse, reason, func(condition *capi.Condition) bool {
+		return IsFalse(condition, WithReason(reason))
+	})
+}
+
+// EnsureUnknown returns nil if the condition of the specified type is not
+// set on the object or it is set with status Unknown. Otherwise it returns
+// ConditionError. It does not panic when the object is nil.
+func EnsureUnknown(object Object, conditionType capi.ConditionType) error {
+	return ensureStatus(object, conditionType, corev1.ConditionUnknown, "", IsUnknown)
+}
+
+func ensureStatus(object Object, conditionType capi.ConditionType, expectedStatus corev1.ConditionStatus, expectedReason string, check CheckOption) error {
+	if isNilGetter(object) {
+		return NewUnexpectedConditionStatusError(object, conditionType, expectedStatus, expectedReason)
+	}
+
+	if IsUnsupported(object, conditionType) {
+		return NewUnsupportedConditionStatusError(object, conditionType)
+	}
+
+	if !check(capiconditions.Get(object, conditionType)) {
+		return NewUnexpectedConditionStatusError(object, conditionType, expectedStatus, expectedReason)
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package conditions
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestEnsure(t *testing.T) {
+	var nilCluster *capi.Cluster
+
+	testCases := []struct {
+		name           string
+		ensure         func() error
+		errorMatcher   func(error) bool
+		expectedFound  bool
+		expectedStatus corev1.ConditionStatus
+		expectedReason string
+		expectedKind   string
+	}{
+		{
+			name: "case 0: EnsureTrue returns nil for condition with status True",
+			ensure: func() error {
+				return EnsureTrue(clusterWith(capi.ReadyCondition, corev1.ConditionTrue), capi.ReadyCondition)
+			},
+		},
+		{
+			name: "case 1: EnsureTrue returns error for condition with status False",
+			ensure: func() error {
+				return EnsureTrue(clusterWith(capi.ReadyCondition, corev1.ConditionFalse), capi.ReadyCondition)
+			},
+			errorMatcher:   IsUnexpectedConditionStatus,
+			expectedFound:  true,
+			expectedStatus: corev1.ConditionFalse,
+			expectedKind:   "Cluster",
+		},
+		{
+			name:         "case 2: EnsureTrue returns error for condition that is not set",
+			ensure:       func() error { return EnsureTrue(machineWithoutConditions(), capi.ReadyCondition) },
+			errorMatcher: IsUnexpectedConditionStatus,
+			expectedKind: "Machine",
+		},
+		{
+			name:         "case 3: EnsureTrue returns error for nil object",
+			ensure:       func() error { return EnsureTrue(nilCluster, capi.ReadyCondition) },
+			errorMatcher: IsUnexpectedConditionStatus,
+		},
+		{
+			name:           "case 4: EnsureTrue returns error for condition with unsupported status",
+			ensure:         func() error { return EnsureTrue(clusterWith(capi.ReadyCondition, "Maybe"), capi.ReadyCondition) },
+			errorMatcher:   IsUnsupportedConditionStatus,
+			expectedFound:  true,
+			expectedStatus: "Maybe",
+			expectedKind:   "Cluster",
+		},
+		{
+			name: "case 5: EnsureFalseWithReason returns nil for condition with expected reason",
+			ensure: func() error {
+				return EnsureFalseWithReason(clusterWithCreatingFalseAnd(corev1.ConditionFalse, UpgradeCompletedReason), Upgrading, UpgradeCompletedReason)
+			},
+		},
+		{
+			name: "case 6: EnsureFalseWithReason returns error for condition with different reason",
+			ensure: func() error {
+				return EnsureFalseWithReason(clusterWithCreatingFalseAnd(corev1.ConditionFalse, UpgradePendingReason), Upgrading, UpgradeCompletedReason)
+			},
+			errorMatcher:   IsUnexpectedConditionStatus,
+			expectedFound:  true,
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: UpgradePendingReason,
+			expectedKind:   "Cluster",
+		},
+		{
+			name: "case 7: EnsureFalse returns error when check is not successful",
+			ensure: func() error {
+				return EnsureFalse(clusterWithCreatingFalseAnd(corev1.ConditionFalse, UpgradePendingReason), Creating, WithSeverityWarning())
+			},
+			errorMatcher:   IsUnexpectedConditionStatus,
+			expectedFound:  true,
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: CreationCompletedReason,
+			expectedKind:   "Cluster",
+		},
+		{
+			name:   "case 8: EnsureUnknown returns nil for condition that is not set",
+			ensure: func() error { return EnsureUnknown(machinePoolWithoutConditions(), capi.ReadyCondition) },
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			err := tc.ensure()
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				return
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			var conditionErr *ConditionError
+			if !errors.As(err, &conditionErr) {
+				t.Fatalf("expected ConditionError, got %#v", err)
+			}
+			if conditionErr.Found != tc.expectedFound || conditionErr.ActualStatus != tc.expectedStatus || conditionErr.ActualReason != tc.expectedReason {
+				t.Fatalf("expected found %t, status %q and reason %q, got %#v", tc.expectedFound, tc.expectedStatus, tc.expectedReason, conditionErr)
+			}
+			if conditionErr.Kind != tc.expectedKind {
+				t.Fatalf("expected kind %q, got %q", tc.expectedKind, conditionErr.Kind)
+			}
+		})
+	}
+}
+
+func TestConditionErrorIsMaskable(t *testing.T) {
+	cluster := clusterWith(capi.ReadyCondition, corev1.ConditionFalse)
+	cluster.ObjectMeta = metav1.ObjectMeta{Namespace: "org-test", Name: "test"}
+
+	err := microerror.Mask(EnsureTrue(cluster, capi.ReadyCondition))
+
+	if !IsUnexpectedConditionStatus(err) {
+		t.Fatalf("expected unexpected condition status error, got %#v", err)
+	}
+	if !errors.Is(err, UnexpectedConditionStatusError) {
+		t.Fatalf("expected errors.Is to match UnexpectedConditionStatusError")
+	}
+
+	var conditionErr *ConditionError
+	if !errors.As(err, &conditionErr) || conditionErr.Name != "test" || conditionErr.ExpectedStatus != corev1.ConditionTrue {
+		t.Fatalf("expected ConditionError for cluster test, got %#v", err)
+	}
+
+	expected := "unexpected condition status: Expected that condition Ready on Cluster org-test/test has status True, but got status False"
+	if conditionErr.Error() != expected {
+		t.Fatalf("expected message %q, got %q", expected, conditionErr.Error())
+	}
+}
+
+func TestUnsupportedConditionStatusErrorMessageWithoutCondition(t *testing.T) {
+	message := UnsupportedConditionStatusErrorMessage(clusterWithoutConditions(), capi.ReadyCondition)
+
+	expected := "Unsupported status for condition Ready, got condition not set"
+	if message != expected {
+		t.Fatalf("expected message %q, got %q", expected, message)
+	}
+}
@@ -3,6 +3,7 @@ package conditions
 import (
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
 	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
 )
@@ -51,49 +52,85 @@ const (
 	WaitingForControlPlaneWarningThresholdTime = 10 * time.Minute
 )
 
-// GetControlPlaneReady tries to get ControlPlaneReady condition from the
-// specified Cluster CR. If the ControlPlaneReady condition was found, it
-// returns a copy of the condition and true, otherwise it returns an empty
-// struct and false.
-func GetControlPlaneReady(cluster *capi.Cluster) (capi.Condition, bool) {
-	controlPlaneReady := capiconditions.Get(cluster, ControlPlaneReady)
+// controlPlaneReadyDescriptor describes ControlPlaneReady condition.
+var controlPlaneReadyDescriptor = ConditionDescriptor{
+	Type: ControlPlaneReady,
+	Reasons: map[string]capi.ConditionSeverity{
+		ControlPlaneReferenceNotSetReason:         capi.ConditionSeverityWarning,
+		ControlPlaneObjectNotFoundReason:          capi.ConditionSeverityWarning,
+		capi.WaitingForControlPlaneFallbackReason: capi.ConditionSeverityInfo,
+	},
+	TargetKinds: []string{"Cluster"},
+}
+
+// ControlPlaneReadyDescriptor returns a copy of the descriptor of
+// ControlPlaneReady condition.
+func ControlPlaneReadyDescriptor() ConditionDescriptor {
+	return controlPlaneReadyDescriptor.deepCopy()
+}
 
-	if controlPlaneReady != nil {
-		return *controlPlaneReady, true
-	} else {
-		return capi.Condition{}, false
-	}
+// GetControlPlaneReady tries to get ControlPlaneReady condition from the
+// specified object. If the ControlPlaneReady condition was found, it returns
+// a copy of the condition and true, otherwise it returns an empty struct and
+// false.
+func GetControlPlaneReady(object Object) (capi.Condition, bool) {
+	return controlPlaneReadyDescriptor.Get(object)
 }
 
-// IsControlPlaneReadyTrue checks if specified cluster is in ControlPlaneReady
+// IsControlPlaneReadyTrue checks if specified object is in ControlPlaneReady
 // condition (if ControlPlaneReady condition is set with status True).
-func IsControlPlaneReadyTrue(cluster *capi.Cluster) bool {
-	return capiconditions.IsTrue(cluster, ControlPlaneReady)
+func IsControlPlaneReadyTrue(object Object) bool {
+	return controlPlaneReadyDescriptor.IsTrue(object)
 }
 
-// IsControlPlaneReadyFalse checks if specified cluster is not in
+// IsControlPlaneReadyFalse checks if specified object is not in
 // ControlPlaneReady condition (if ControlPlaneReady condition is set with
 // status False) and if optionally specified checks are successful.
-func IsControlPlaneReadyFalse(cluster *capi.Cluster, checkOptions ...CheckOption) bool {
-	condition := capiconditions.Get(cluster, ControlPlaneReady)
-	if !IsFalse(condition) {
-		// Condition is not set or it does not have status False
-		return false
-	}
-
-	for _, checkOption := range checkOptions {
-		if !checkOption(condition) {
-			// additional check has failed
-			return false
-		}
-	}
-
-	return true
+func IsControlPlaneReadyFalse(object Object, checkOptions ...CheckOption) bool {
+	return controlPlaneReadyDescriptor.IsFalse(object, checkOptions...)
 }
 
 // IsControlPlaneReadyUnknown checks if it is unknown whether the specified
-// cluster is in ControlPlaneReady condition or not (if ControlPlaneReady
+// object is in ControlPlaneReady condition or not (if ControlPlaneReady
 // condition is not set, or it is set with status Unknown).
-func IsControlPlaneReadyUnknown(cluster *capi.Cluster) bool {
-	return capiconditions.IsUnknown(cluster, ControlPlaneReady)
+func IsControlPlaneReadyUnknown(object Object) bool {
+	return controlPlaneReadyDescriptor.IsUnknown(object)
+}
+
+var controlPlaneReadyMirrorSpec = mirrorSpec{
+	descriptor:            &controlPlaneReadyDescriptor,
+	description:           "Control plane",
+	referenceNotSetReason: ControlPlaneReferenceNotSetReason,
+	objectNotFoundReason:  ControlPlaneObjectNotFoundReason,
+	waitingReason:         capi.WaitingForControlPlaneFallbackReason,
+	warningThresholdTime:  WaitingForControlPlaneWarningThresholdTime,
+}
+
+// UpdateControlPlaneReady sets ControlPlaneReady condition on the specified
+// object by mirroring Ready condition from the referenced control plane
+// object, e.g. from AzureMachine for Cluster CR. The condition is set in the
+// following way:
+//
+//   - When the reference is not set, it is set with status False, reason
+//     ControlPlaneReferenceNotSet and severity Warning.
+//   - When the referenced object is nil (i.e. it is not found), it is set with
+//     status False, reason ControlPlaneObjectNotFound and severity Warning.
+//   - When the referenced object does not have Ready condition, it is set
+//     with status False, reason WaitingForControlPlane and severity Info.
+//   - Otherwise, Ready condition of the referenced object is mirrored.
+//
+// Severity Info is escalated to Warning after
+// WaitingForControlPlaneWarningThresholdTime (see EscalateSeverity). It
+// returns the duration after which the next severity escalation is due, or 0.
+// Current time is taken from the clock set with WithClock.
+//
+// Example:
+//
+//    requeueAfter := conditions.UpdateControlPlaneReady(
+//        cluster,
+//        cluster.Spec.ControlPlaneRef,
+//        azureMachine) // nil when AzureMachine is not found
+//
+func UpdateControlPlaneReady(object Object, controlPlaneRef *corev1.ObjectReference, controlPlaneObject capiconditions.Getter, options ...TimeOption) time.Duration {
+	return updateMirror(object, controlPlaneReadyMirrorSpec, controlPlaneRef, controlPlaneObject, clockOf(options))
 }
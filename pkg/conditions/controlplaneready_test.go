@@ -7,6 +7,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
 )
 
 func TestGetControlPlaneReady(t *testing.T) {
@@ -220,3 +221,49 @@ func TestIsControlPlaneReadyUnknown(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateControlPlaneReady(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	cluster := clusterWithoutConditions()
+	reference := &corev1.ObjectReference{Kind: "AzureMachine", Namespace: "org-test", Name: "test-master-0"}
+	fakeClock := NewFakeClock(now)
+	clock := WithClock(fakeClock)
+
+	// Control plane object is not found.
+	requeueAfter := UpdateControlPlaneReady(cluster, reference, nil, clock)
+	if !IsControlPlaneReadyFalse(cluster, WithReason(ControlPlaneObjectNotFoundReason), WithSeverityWarning()) || requeueAfter != 0 {
+		t.Fatalf("unexpected %s and RequeueAfter %s", sprintConditionForObject(cluster, ControlPlaneReady), requeueAfter)
+	}
+
+	// Control plane object is found, but it does not have Ready condition.
+	controlPlane := clusterWithoutConditions()
+	requeueAfter = UpdateControlPlaneReady(cluster, reference, controlPlane, clock)
+	if !IsControlPlaneReadyFalse(cluster, WithReason(capi.WaitingForControlPlaneFallbackReason), WithSeverityInfo()) ||
+		requeueAfter != WaitingForControlPlaneWarningThresholdTime {
+		t.Fatalf("unexpected %s and RequeueAfter %s", sprintConditionForObject(cluster, ControlPlaneReady), requeueAfter)
+	}
+
+	// Still waiting after threshold time, severity is escalated.
+	fakeClock.Step(WaitingForControlPlaneWarningThresholdTime)
+	requeueAfter = UpdateControlPlaneReady(cluster, reference, controlPlane, clock)
+	if !IsControlPlaneReadyFalse(cluster, WithReason(capi.WaitingForControlPlaneFallbackReason), WithSeverityWarning()) || requeueAfter != 0 {
+		t.Fatalf("unexpected %s and RequeueAfter %s", sprintConditionForObject(cluster, ControlPlaneReady), requeueAfter)
+	}
+	lastTransitionTime := capiconditions.GetLastTransitionTime(cluster, ControlPlaneReady)
+	if !lastTransitionTime.Time.Equal(now) {
+		t.Fatalf("expected LastTransitionTime %s, got %s", now, lastTransitionTime)
+	}
+
+	// Control plane is ready.
+	capiconditions.MarkTrue(controlPlane, capi.ReadyCondition)
+	UpdateControlPlaneReady(cluster, reference, controlPlane, clock)
+	if !IsControlPlaneReadyTrue(cluster) {
+		t.Fatalf("unexpected %s", sprintConditionForObject(cluster, ControlPlaneReady))
+	}
+
+	// Control plane reference is removed.
+	UpdateControlPlaneReady(cluster, nil, controlPlane, clock)
+	if !IsControlPlaneReadyFalse(cluster, WithReason(ControlPlaneReferenceNotSetReason), WithSeverityWarning()) {
+		t.Fatalf("unexpected %s", sprintConditionForObject(cluster, ControlPlaneReady))
+	}
+}
@@ -1,6 +1,7 @@
 package conditions
 
 import (
+	"github.com/giantswarm/microerror"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
 	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
 )
@@ -24,23 +25,31 @@ const (
 	ExistingObjectReason = "ExistingObject"
 )
 
+// creatingDescriptor describes Creating condition.
+var creatingDescriptor = ConditionDescriptor{
+	Type: Creating,
+	Reasons: map[string]capi.ConditionSeverity{
+		CreationCompletedReason: capi.ConditionSeverityInfo,
+		ExistingObjectReason:    capi.ConditionSeverityInfo,
+	},
+}
+
+// CreatingDescriptor returns a copy of the descriptor of Creating condition.
+func CreatingDescriptor() ConditionDescriptor {
+	return creatingDescriptor.deepCopy()
+}
+
 // GetCreating tries to get Creating condition from the specified object. If
 // the Creating condition was found, it returns a copy of the condition and
 // true, otherwise it returns an empty struct and false.
 func GetCreating(object Object) (capi.Condition, bool) {
-	c := capiconditions.Get(object, Creating)
-
-	if c != nil {
-		return *c, true
-	} else {
-		return capi.Condition{}, false
-	}
+	return creatingDescriptor.Get(object)
 }
 
 // IsCreatingTrue checks if specified object is in Creating condition (if
 // Creating condition is set with status True).
 func IsCreatingTrue(object Object) bool {
-	return capiconditions.IsTrue(object, Creating)
+	return creatingDescriptor.IsTrue(object)
 }
 
 // IsCreatingFalse checks if specified object is not in Creating condition (if
@@ -54,27 +63,14 @@ func IsCreatingTrue(object Object) bool {
 //    IsCreatingFalse(cluster, WithExistingObjectReason())
 //
 func IsCreatingFalse(object Object, checkOptions ...CheckOption) bool {
-	creating := capiconditions.Get(object, Creating)
-	if !IsFalse(creating) {
-		// Creating condition is not set or it does not have status False
-		return false
-	}
-
-	for _, checkOption := range checkOptions {
-		if !checkOption(creating) {
-			// additional check has failed
-			return false
-		}
-	}
-
-	return true
+	return creatingDescriptor.IsFalse(object, checkOptions...)
 }
 
 // IsCreatingUnknown checks if it is unknown whether the specified object is in
 // Creating condition or not (if Creating condition is not set, or it is set
 // with status Unknown).
 func IsCreatingUnknown(object Object) bool {
-	return capiconditions.IsUnknown(object, Creating)
+	return creatingDescriptor.IsUnknown(object)
 }
 
 // WithCreationCompletedReason returns a CheckOption that checks if condition
@@ -88,3 +84,66 @@ func WithCreationCompletedReason() CheckOption {
 func WithExistingObjectReason() CheckOption {
 	return WithReason(ExistingObjectReason)
 }
+
+// MarkCreatingTrue sets Creating condition with status True on the specified
+// object. It returns InvalidLifecycleTransitionError if the creation has
+// already been completed, since an object cannot be created again. Current
+// time is taken from the clock set with WithClock.
+func MarkCreatingTrue(object Object, options ...TimeOption) error {
+	err := setLifecycleCondition(object, capiconditions.TrueCondition(Creating), options...)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// MarkCreationCompleted sets Creating condition with status False, reason
+// CreationCompleted and severity Info on the specified object. Creation can
+// be completed only while Creating condition has status True, otherwise
+// InvalidLifecycleTransitionError is returned. If the creation has already
+// been completed, the condition is not changed. Current time is taken from
+// the clock set with WithClock.
+func MarkCreationCompleted(object Object, options ...TimeOption) error {
+	if IsCreatingFalse(object) {
+		// Creation has already been completed, nothing to do here.
+		return nil
+	}
+
+	condition := creatingDescriptor.falseCondition(
+		CreationCompletedReason,
+		"Creation has been completed")
+
+	err := setLifecycleCondition(object, condition, options...)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// MarkExistingObject sets Creating condition with status False, reason
+// ExistingObject and severity Info on the specified object. It should be
+// used for objects that were created before the Creating condition was
+// introduced, so it can be set whenever Creating condition is not set, also
+// when Upgrading condition is already set. It returns
+// InvalidLifecycleTransitionError if the object is currently being created.
+// If the creation has already been completed, the condition is not changed.
+// Current time is taken from the clock set with WithClock.
+func MarkExistingObject(object Object, options ...TimeOption) error {
+	if IsCreatingFalse(object) {
+		// Creation has already been completed, nothing to do here.
+		return nil
+	}
+
+	condition := creatingDescriptor.falseCondition(
+		ExistingObjectReason,
+		"Object was created before Creating condition was introduced")
+
+	err := setLifecycleCondition(object, condition, options...)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
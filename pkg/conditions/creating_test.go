@@ -318,3 +318,210 @@ func TestIsCreatingUnknown(t *testing.T) {
 		})
 	}
 }
+
+func TestMarkCreatingTrue(t *testing.T) {
+	testCases := []struct {
+		name         string
+		object       Object
+		errorMatcher func(error) bool
+	}{
+		{
+			name:   "case 0: Creating is set to True for CR without condition Creating",
+			object: clusterWithoutConditions(),
+		},
+		{
+			name:   "case 1: Creating is set to True for CR with condition Creating with status Unknown",
+			object: machinePoolWith(Creating, corev1.ConditionUnknown),
+		},
+		{
+			name:   "case 2: Creating stays True for CR with condition Creating with status True",
+			object: clusterWith(Creating, corev1.ConditionTrue),
+		},
+		{
+			name:         "case 3: Error is returned for CR with condition Creating with status False",
+			object:       clusterWith(Creating, corev1.ConditionFalse),
+			errorMatcher: IsInvalidLifecycleTransition,
+		},
+		{
+			name:         "case 4: Error is returned for CR with condition Creating with unsupported status",
+			object:       machinePoolWith(Creating, "SomethingElse"),
+			errorMatcher: IsUnsupportedConditionStatus,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			err := MarkCreatingTrue(tc.object)
+
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Logf("expected matching error, got %#v", err)
+					t.Fail()
+				}
+				return
+			} else if err != nil {
+				t.Logf("expected no error, got %#v", err)
+				t.Fail()
+			}
+
+			if !IsCreatingTrue(tc.object) {
+				t.Logf("Creating was not set correctly, got %s", sprintConditionForObject(tc.object, Creating))
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestMarkCreationCompleted(t *testing.T) {
+	testCases := []struct {
+		name           string
+		object         Object
+		errorMatcher   func(error) bool
+		expectedReason string
+	}{
+		{
+			name:           "case 0: Creation is completed for CR with condition Creating with status True",
+			object:         clusterWith(Creating, corev1.ConditionTrue),
+			expectedReason: CreationCompletedReason,
+		},
+		{
+			name: "case 1: Creating is not changed for CR with condition Creating with status False",
+			object: &capi.Cluster{
+				Status: capi.ClusterStatus{
+					Conditions: capi.Conditions{
+						{
+							Type:     Creating,
+							Status:   corev1.ConditionFalse,
+							Reason:   ExistingObjectReason,
+							Severity: capi.ConditionSeverityInfo,
+						},
+					},
+				},
+			},
+			expectedReason: ExistingObjectReason,
+		},
+		{
+			name:         "case 2: Error is returned for CR without condition Creating",
+			object:       machinePoolWithoutConditions(),
+			errorMatcher: IsInvalidLifecycleTransition,
+		},
+		{
+			name:         "case 3: Error is returned for CR with condition Creating with status Unknown",
+			object:       clusterWith(Creating, corev1.ConditionUnknown),
+			errorMatcher: IsInvalidLifecycleTransition,
+		},
+		{
+			name:         "case 4: Error is returned for CR with condition Creating with unsupported status",
+			object:       clusterWith(Creating, "Maybe"),
+			errorMatcher: IsUnsupportedConditionStatus,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			err := MarkCreationCompleted(tc.object)
+
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Logf("expected matching error, got %#v", err)
+					t.Fail()
+				}
+				return
+			} else if err != nil {
+				t.Logf("expected no error, got %#v", err)
+				t.Fail()
+			}
+
+			if !IsCreatingFalse(tc.object, WithReason(tc.expectedReason), WithSeverityInfo()) {
+				t.Logf(
+					"Creating was not set correctly, got %s, expected reason %q",
+					sprintConditionForObject(tc.object, Creating),
+					tc.expectedReason)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestMarkExistingObject(t *testing.T) {
+	testCases := []struct {
+		name           string
+		object         Object
+		errorMatcher   func(error) bool
+		expectedReason string
+	}{
+		{
+			name:           "case 0: Creating is set for CR without condition Creating",
+			object:         clusterWithoutConditions(),
+			expectedReason: ExistingObjectReason,
+		},
+		{
+			name:           "case 1: Creating is set for CR with condition Creating with status Unknown",
+			object:         machinePoolWith(Creating, corev1.ConditionUnknown),
+			expectedReason: ExistingObjectReason,
+		},
+		{
+			name: "case 2: Creating is not changed for CR with condition Creating with status False",
+			object: &capi.Cluster{
+				Status: capi.ClusterStatus{
+					Conditions: capi.Conditions{
+						{
+							Type:     Creating,
+							Status:   corev1.ConditionFalse,
+							Reason:   CreationCompletedReason,
+							Severity: capi.ConditionSeverityInfo,
+						},
+					},
+				},
+			},
+			expectedReason: CreationCompletedReason,
+		},
+		{
+			name:         "case 3: Error is returned for CR with condition Creating with status True",
+			object:       clusterWith(Creating, corev1.ConditionTrue),
+			errorMatcher: IsInvalidLifecycleTransition,
+		},
+		{
+			name: "case 4: Creating is set for CR with condition Upgrading, but without condition Creating",
+			object: &capi.Cluster{
+				Status: capi.ClusterStatus{
+					Conditions: capi.Conditions{
+						{Type: Upgrading, Status: corev1.ConditionFalse, Reason: UpgradeCompletedReason, Severity: capi.ConditionSeverityInfo},
+					},
+				},
+			},
+			expectedReason: ExistingObjectReason,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			err := MarkExistingObject(tc.object)
+
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Logf("expected matching error, got %#v", err)
+					t.Fail()
+				}
+				return
+			} else if err != nil {
+				t.Logf("expected no error, got %#v", err)
+				t.Fail()
+			}
+
+			if !IsCreatingFalse(tc.object, WithReason(tc.expectedReason), WithSeverityInfo()) {
+				t.Logf(
+					"Creating was not set correctly, got %s, expected reason %q",
+					sprintConditionForObject(tc.object, Creating),
+					tc.expectedReason)
+				t.Fail()
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package conditions
+
+import (
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// ConditionDescriptor describes a condition type, together with its known
+// reasons and the kinds of objects on which the condition is set. It
+// provides accessors that work with any object that has conditions, so a new
+// condition type can be supported by declaring its descriptor. Descriptors
+// of condition types from this package are returned as copies by accessor
+// functions, e.g. CreatingDescriptor, so that package defaults cannot be
+// changed by importers.
+//
+// Example:
+//
+//    var myConditionDescriptor = ConditionDescriptor{Type: "MyCondition"}
+//
+//    myConditionDescriptor.IsFalse(azureCluster, WithSeverityWarning())
+//
+type ConditionDescriptor struct {
+	// Type is the condition type.
+	Type capi.ConditionType
+
+	// Reasons contains known condition reasons, mapped to the severity that
+	// should be used by default when condition is set to False with the
+	// reason.
+	Reasons map[string]capi.ConditionSeverity
+
+	// TargetKinds contains kinds of objects on which the condition is set.
+	// When empty, the condition can be set on objects of any kind.
+	TargetKinds []string
+}
+
+// Get tries to get the condition from the specified object. If the condition
+// was found, it returns a copy of the condition and true, otherwise it
+// returns an empty struct and false.
+func (d ConditionDescriptor) Get(object capiconditions.Getter) (capi.Condition, bool) {
+	c := capiconditions.Get(object, d.Type)
+
+	if c != nil {
+		return *c, true
+	} else {
+		return capi.Condition{}, false
+	}
+}
+
+// IsTrue checks if the condition is set on the specified object with status
+// True.
+func (d ConditionDescriptor) IsTrue(object capiconditions.Getter) bool {
+	return IsTrue(capiconditions.Get(object, d.Type))
+}
+
+// IsFalse checks if the condition is set on the specified object with status
+// False and if optionally specified checks are successful.
+func (d ConditionDescriptor) IsFalse(object capiconditions.Getter, checkOptions ...CheckOption) bool {
+	return IsFalse(capiconditions.Get(object, d.Type), checkOptions...)
+}
+
+// IsUnknown checks if the condition is either not set on the specified
+// object or it is set with status Unknown.
+func (d ConditionDescriptor) IsUnknown(object capiconditions.Getter) bool {
+	return IsUnknown(capiconditions.Get(object, d.Type))
+}
+
+// HasReason checks if the specified reason is a known reason for the
+// condition.
+func (d ConditionDescriptor) HasReason(reason string) bool {
+	_, ok := d.Reasons[reason]
+	return ok
+}
+
+// DefaultSeverity returns the default severity for the specified reason and
+// true. If the reason is not known, it returns severity Info and false.
+func (d ConditionDescriptor) DefaultSeverity(reason string) (capi.ConditionSeverity, bool) {
+	severity, ok := d.Reasons[reason]
+	if !ok {
+		return capi.ConditionSeverityInfo, false
+	}
+
+	return severity, true
+}
+
+// SupportsKind checks if the condition can be set on objects of the
+// specified kind.
+func (d ConditionDescriptor) SupportsKind(kind string) bool {
+	if len(d.TargetKinds) == 0 {
+		return true
+	}
+
+	for _, targetKind := range d.TargetKinds {
+		if targetKind == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deepCopy returns a copy of the descriptor that does not share reasons and
+// target kinds with the original.
+func (d ConditionDescriptor) deepCopy() ConditionDescriptor {
+	result := ConditionDescriptor{Type: d.Type}
+	if d.Reasons != nil {
+		result.Reasons = make(map[string]capi.ConditionSeverity, len(d.Reasons))
+		for reason, severity := range d.Reasons {
+			result.Reasons[reason] = severity
+		}
+	}
+	if d.TargetKinds != nil {
+		result.TargetKinds = append([]string{}, d.TargetKinds...)
+	}
+
+	return result
+}
+
+// falseCondition returns the condition with status False, the specified
+// reason and message, and the default severity for the reason (see
+// DefaultSeverity).
+func (d ConditionDescriptor) falseCondition(reason string, messageFormat string, messageArgs ...interface{}) *capi.Condition {
+	severity, _ := d.DefaultSeverity(reason)
+	return capiconditions.FalseCondition(d.Type, reason, severity, messageFormat, messageArgs...)
+}
+
+// MarkTrue sets the condition on the specified object with status True.
+// Current time is taken from the clock set with WithClock.
+func (d ConditionDescriptor) MarkTrue(object Object, options ...TimeOption) {
+	setCondition(object, *capiconditions.TrueCondition(d.Type), clockOf(options))
+}
+
+// MarkFalse sets the condition on the specified object with status False,
+// the specified reason and message, and the default severity for the reason
+// (see DefaultSeverity). Current time is taken from the clock set with
+// WithClock.
+func (d ConditionDescriptor) MarkFalse(object Object, reason string, message string, options ...TimeOption) {
+	setCondition(object, *d.falseCondition(reason, "%s", message), clockOf(options))
+}
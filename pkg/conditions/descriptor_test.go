@@ -0,0 +1,179 @@
+package conditions
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestConditionDescriptorAccessors(t *testing.T) {
+	descriptor := ConditionDescriptor{Type: "MyCondition"}
+
+	testCases := []struct {
+		name            string
+		object          capiconditions.Getter
+		checkOptions    []CheckOption
+		expectedTrue    bool
+		expectedFalse   bool
+		expectedUnknown bool
+		expectedFound   bool
+	}{
+		{
+			name:          "case 0: Condition with status True on Cluster",
+			object:        clusterWith("MyCondition", corev1.ConditionTrue),
+			expectedTrue:  true,
+			expectedFound: true,
+		},
+		{
+			name:          "case 1: Condition with status False on Machine",
+			object:        machineWith("MyCondition", corev1.ConditionFalse),
+			expectedFalse: true,
+			expectedFound: true,
+		},
+		{
+			name:          "case 2: Condition with status False on MachinePool with failing check option",
+			object:        machinePoolWith("MyCondition", corev1.ConditionFalse),
+			checkOptions:  []CheckOption{WithReason("Whatever")},
+			expectedFound: true,
+		},
+		{
+			name:            "case 3: Condition with status Unknown",
+			object:          clusterWith("MyCondition", corev1.ConditionUnknown),
+			expectedUnknown: true,
+			expectedFound:   true,
+		},
+		{
+			name:            "case 4: Condition is not set",
+			object:          machineWithoutConditions(),
+			expectedUnknown: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			if result := descriptor.IsTrue(tc.object); result != tc.expectedTrue {
+				t.Logf("expected IsTrue to return %t, got %t", tc.expectedTrue, result)
+				t.Fail()
+			}
+			if result := descriptor.IsFalse(tc.object, tc.checkOptions...); result != tc.expectedFalse {
+				t.Logf("expected IsFalse to return %t, got %t", tc.expectedFalse, result)
+				t.Fail()
+			}
+			if result := descriptor.IsUnknown(tc.object); result != tc.expectedUnknown {
+				t.Logf("expected IsUnknown to return %t, got %t", tc.expectedUnknown, result)
+				t.Fail()
+			}
+			if condition, found := descriptor.Get(tc.object); found != tc.expectedFound || (found && condition.Type != descriptor.Type) {
+				t.Logf("expected Get to return found=%t, got %s", tc.expectedFound, sprintCondition(&condition))
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestConditionDescriptorMarkFalse(t *testing.T) {
+	testCases := []struct {
+		name             string
+		reason           string
+		expectedSeverity capi.ConditionSeverity
+	}{
+		{
+			name:             "case 0: Default severity Warning is used for reason InfrastructureObjectNotFound",
+			reason:           InfrastructureObjectNotFoundReason,
+			expectedSeverity: capi.ConditionSeverityWarning,
+		},
+		{
+			name:             "case 1: Default severity Info is used for reason WaitingForInfrastructure",
+			reason:           capi.WaitingForInfrastructureFallbackReason,
+			expectedSeverity: capi.ConditionSeverityInfo,
+		},
+		{
+			name:             "case 2: Severity Info is used for unknown reason",
+			reason:           "SomethingHappened",
+			expectedSeverity: capi.ConditionSeverityInfo,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+			machinePool := machinePoolWithoutConditions()
+			now := time.Date(2022, 4, 1, 12, 0, 0, 0, time.UTC)
+
+			InfrastructureReadyDescriptor().MarkFalse(machinePool, tc.reason, "Message for test", WithClock(NewFakeClock(now)))
+
+			if !IsInfrastructureReadyFalse(machinePool, WithReason(tc.reason), WithSeverity(tc.expectedSeverity)) {
+				t.Logf("unexpected %s", sprintConditionForObject(machinePool, InfrastructureReady))
+				t.Fail()
+			}
+			if condition, _ := GetInfrastructureReady(machinePool); !condition.LastTransitionTime.Time.Equal(now) {
+				t.Logf("expected LastTransitionTime %s, got %s", now, condition.LastTransitionTime)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestConditionDescriptorSupportsKind(t *testing.T) {
+	testCases := []struct {
+		name           string
+		descriptor     ConditionDescriptor
+		kind           string
+		expectedOutput bool
+	}{
+		{
+			name:           "case 0: ControlPlaneReady is supported on Cluster",
+			descriptor:     ControlPlaneReadyDescriptor(),
+			kind:           "Cluster",
+			expectedOutput: true,
+		},
+		{
+			name:           "case 1: ControlPlaneReady is not supported on MachinePool",
+			descriptor:     ControlPlaneReadyDescriptor(),
+			kind:           "MachinePool",
+			expectedOutput: false,
+		},
+		{
+			name:           "case 2: Ready is supported on any kind",
+			descriptor:     ReadyDescriptor(),
+			kind:           "AzureCluster",
+			expectedOutput: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			result := tc.descriptor.SupportsKind(tc.kind)
+			if result != tc.expectedOutput {
+				t.Logf("expected %t, got %t", tc.expectedOutput, result)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestConditionDescriptorCannotBeChanged(t *testing.T) {
+	descriptor := InfrastructureReadyDescriptor()
+	descriptor.Reasons[InfrastructureReferenceNotSetReason] = capi.ConditionSeverityError
+	descriptor.TargetKinds[0] = "AzureCluster"
+
+	if severity, _ := InfrastructureReadyDescriptor().DefaultSeverity(InfrastructureReferenceNotSetReason); severity != capi.ConditionSeverityWarning {
+		t.Fatalf("expected default severity %q, got %q", capi.ConditionSeverityWarning, severity)
+	}
+	if !InfrastructureReadyDescriptor().SupportsKind("Cluster") {
+		t.Fatalf("expected InfrastructureReady to be supported on Cluster")
+	}
+
+	cluster := clusterWithoutConditions()
+	UpdateInfrastructureReady(cluster, nil, nil)
+	if !IsInfrastructureReadyFalse(cluster, WithSeverityWarning()) {
+		t.Fatalf("expected default severity to be used, got %s", sprintConditionForObject(cluster, InfrastructureReady))
+	}
+}
@@ -0,0 +1,225 @@
+package conditions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// ChangeKind tells what has changed in a condition. When multiple condition
+// fields have changed, the most significant change is used, where Status is
+// the most significant field and LastTransitionTime is the least significant
+// one.
+type ChangeKind string
+
+const (
+	// ChangeKindAdded is used when the condition is set only in the new
+	// object version.
+	ChangeKindAdded ChangeKind = "Added"
+
+	// ChangeKindRemoved is used when the condition is set only in the old
+	// object version.
+	ChangeKindRemoved ChangeKind = "Removed"
+
+	// ChangeKindStatus is used when the condition status has changed.
+	ChangeKindStatus ChangeKind = "StatusChanged"
+
+	// ChangeKindReason is used when the condition reason has changed, but the
+	// status is the same.
+	ChangeKindReason ChangeKind = "ReasonChanged"
+
+	// ChangeKindSeverity is used when the condition severity has changed, but
+	// the status and reason are the same.
+	ChangeKindSeverity ChangeKind = "SeverityChanged"
+
+	// ChangeKindMessage is used when only the condition message has changed.
+	ChangeKindMessage ChangeKind = "MessageChanged"
+
+	// ChangeKindLastTransitionTime is used when only the condition
+	// LastTransitionTime has changed.
+	ChangeKindLastTransitionTime ChangeKind = "LastTransitionTimeChanged"
+)
+
+// ConditionChange describes how a single condition has changed between two
+// object versions. Before is nil when the condition has been added, and After
+// is nil when the condition has been removed.
+type ConditionChange struct {
+	Type   capi.ConditionType
+	Kind   ChangeKind
+	Before *capi.Condition
+	After  *capi.Condition
+}
+
+// String returns a human-readable description of the change.
+func (c ConditionChange) String() string {
+	switch c.Kind {
+	case ChangeKindAdded:
+		return fmt.Sprintf("%s added: %s", c.Type, sprintConditionState(c.After))
+	case ChangeKindRemoved:
+		return fmt.Sprintf("%s removed: %s", c.Type, sprintConditionState(c.Before))
+	default:
+		return fmt.Sprintf("%s %s: %s -> %s", c.Type, c.Kind, sprintConditionState(c.Before), sprintConditionState(c.After))
+	}
+}
+
+// ConditionChanges contains changes of all conditions between two object
+// versions, ordered by condition type.
+type ConditionChanges []ConditionChange
+
+// Get returns the change of the condition with the specified type and true.
+// If the condition has not changed, it returns an empty struct and false.
+func (c ConditionChanges) Get(conditionType capi.ConditionType) (ConditionChange, bool) {
+	for _, change := range c {
+		if change.Type == conditionType {
+			return change, true
+		}
+	}
+
+	return ConditionChange{}, false
+}
+
+// String returns a human-readable description of all changes, one change
+// per line.
+func (c ConditionChanges) String() string {
+	if len(c) == 0 {
+		return "no condition changes"
+	}
+
+	lines := make([]string, 0, len(c))
+	for _, change := range c {
+		lines = append(lines, change.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+type diffOptions struct {
+	equivalence bool
+}
+
+// DiffOption is an option for Diff.
+type DiffOption func(options *diffOptions)
+
+// WithEquivalence returns a DiffOption that makes Diff compare conditions
+// with AreEquivalent instead of AreEqual, i.e. changes of Message and
+// LastTransitionTime are ignored.
+func WithEquivalence() DiffOption {
+	return func(options *diffOptions) {
+		options.equivalence = true
+	}
+}
+
+// Diff compares conditions of two versions of an object and returns all
+// changed conditions. Nil object is handled as an object without conditions.
+//
+// Example:
+//
+//    before := cluster.DeepCopy()
+//    // reconcile cluster ...
+//    changes := conditions.Diff(before, cluster, conditions.WithEquivalence())
+//    logger.Debugf(ctx, "condition changes:\n%s", changes)
+//
+func Diff(before, after capiconditions.Getter, options ...DiffOption) ConditionChanges {
+	return diffConditions(conditionsOf(before), conditionsOf(after), options...)
+}
+
+func diffConditions(before, after capi.Conditions, options ...DiffOption) ConditionChanges {
+	diffOpts := diffOptions{}
+	for _, option := range options {
+		option(&diffOpts)
+	}
+
+	beforeConditions := conditionsByType(before)
+	afterConditions := conditionsByType(after)
+
+	var conditionTypes []capi.ConditionType
+	for conditionType := range beforeConditions {
+		conditionTypes = append(conditionTypes, conditionType)
+	}
+	for conditionType := range afterConditions {
+		if _, ok := beforeConditions[conditionType]; !ok {
+			conditionTypes = append(conditionTypes, conditionType)
+		}
+	}
+	sort.Slice(conditionTypes, func(i, j int) bool {
+		return conditionTypes[i] < conditionTypes[j]
+	})
+
+	var changes ConditionChanges
+	for _, conditionType := range conditionTypes {
+		c1 := beforeConditions[conditionType]
+		c2 := afterConditions[conditionType]
+
+		if diffOpts.equivalence && AreEquivalent(c1, c2) || !diffOpts.equivalence && AreEqual(c1, c2) {
+			continue
+		}
+
+		changes = append(changes, ConditionChange{
+			Type:   conditionType,
+			Kind:   changeKind(c1, c2),
+			Before: c1,
+			After:  c2,
+		})
+	}
+
+	return changes
+}
+
+// changeKind returns the most significant change between two different
+// conditions.
+func changeKind(before, after *capi.Condition) ChangeKind {
+	switch {
+	case before == nil:
+		return ChangeKindAdded
+	case after == nil:
+		return ChangeKindRemoved
+	case before.Status != after.Status:
+		return ChangeKindStatus
+	case before.Reason != after.Reason:
+		return ChangeKindReason
+	case before.Severity != after.Severity:
+		return ChangeKindSeverity
+	case before.Message != after.Message:
+		return ChangeKindMessage
+	default:
+		return ChangeKindLastTransitionTime
+	}
+}
+
+// conditionsOf returns conditions of the specified object, or nil if the
+// object is nil.
+func conditionsOf(getter capiconditions.Getter) capi.Conditions {
+	if isNilGetter(getter) {
+		return nil
+	}
+
+	return getter.GetConditions()
+}
+
+func conditionsByType(conditions capi.Conditions) map[capi.ConditionType]*capi.Condition {
+	result := map[capi.ConditionType]*capi.Condition{}
+	for i := range conditions {
+		condition := conditions[i]
+		result[condition.Type] = &condition
+	}
+
+	return result
+}
+
+func sprintConditionState(condition *capi.Condition) string {
+	text := fmt.Sprintf("Status=%s", condition.Status)
+	if condition.Reason != "" {
+		text += fmt.Sprintf(", Reason=%s", condition.Reason)
+	}
+	if condition.Severity != capi.ConditionSeverityNone {
+		text += fmt.Sprintf(", Severity=%s", condition.Severity)
+	}
+	if condition.Message != "" {
+		text += fmt.Sprintf(", Message=%q", condition.Message)
+	}
+
+	return text
+}
@@ -0,0 +1,145 @@
+package conditions
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestDiff(t *testing.T) {
+	before := time.Date(2021, 1, 1, 11, 0, 0, 0, time.UTC)
+	after := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	oldCluster := &capi.Cluster{
+		Status: capi.ClusterStatus{
+			Conditions: capi.Conditions{
+				{Type: capi.ReadyCondition, Status: corev1.ConditionFalse, Reason: "Provisioning", Severity: capi.ConditionSeverityInfo, LastTransitionTime: metav1.NewTime(before)},
+				{Type: Creating, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(before)},
+				{Type: ControlPlaneReady, Status: corev1.ConditionFalse, Reason: "Provisioning", Severity: capi.ConditionSeverityInfo, LastTransitionTime: metav1.NewTime(before)},
+				{Type: InfrastructureReady, Status: corev1.ConditionFalse, Reason: "Provisioning", Severity: capi.ConditionSeverityInfo, LastTransitionTime: metav1.NewTime(before)},
+				{Type: NodePoolsReady, Status: corev1.ConditionFalse, Reason: NodePoolsNotReadyReason, Message: "0 of 1", LastTransitionTime: metav1.NewTime(before)},
+				{Type: Upgrading, Status: corev1.ConditionFalse, Reason: UpgradeNotStartedReason, LastTransitionTime: metav1.NewTime(before)},
+			},
+		},
+	}
+	newCluster := &capi.Cluster{
+		Status: capi.ClusterStatus{
+			Conditions: capi.Conditions{
+				// Status changed.
+				{Type: capi.ReadyCondition, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(after)},
+				// Creating removed.
+				// Reason changed.
+				{Type: ControlPlaneReady, Status: corev1.ConditionFalse, Reason: "Scaling", Severity: capi.ConditionSeverityInfo, LastTransitionTime: metav1.NewTime(after)},
+				// Severity changed.
+				{Type: InfrastructureReady, Status: corev1.ConditionFalse, Reason: "Provisioning", Severity: capi.ConditionSeverityWarning, LastTransitionTime: metav1.NewTime(before)},
+				// Message changed.
+				{Type: NodePoolsReady, Status: corev1.ConditionFalse, Reason: NodePoolsNotReadyReason, Message: "0 of 2", LastTransitionTime: metav1.NewTime(after)},
+				// LastTransitionTime changed.
+				{Type: Upgrading, Status: corev1.ConditionFalse, Reason: UpgradeNotStartedReason, LastTransitionTime: metav1.NewTime(after)},
+				// Added.
+				{Type: capi.MachinesReadyCondition, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(after)},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		before        Object
+		after         Object
+		options       []DiffOption
+		expectedKinds map[capi.ConditionType]ChangeKind
+	}{
+		{
+			name:   "case 0: All changes are returned",
+			before: oldCluster,
+			after:  newCluster,
+			expectedKinds: map[capi.ConditionType]ChangeKind{
+				capi.ReadyCondition:         ChangeKindStatus,
+				Creating:                    ChangeKindRemoved,
+				ControlPlaneReady:           ChangeKindReason,
+				InfrastructureReady:         ChangeKindSeverity,
+				NodePoolsReady:              ChangeKindMessage,
+				Upgrading:                   ChangeKindLastTransitionTime,
+				capi.MachinesReadyCondition: ChangeKindAdded,
+			},
+		},
+		{
+			name:    "case 1: Message and LastTransitionTime changes are ignored with equivalence",
+			before:  oldCluster,
+			after:   newCluster,
+			options: []DiffOption{WithEquivalence()},
+			expectedKinds: map[capi.ConditionType]ChangeKind{
+				capi.ReadyCondition:         ChangeKindStatus,
+				Creating:                    ChangeKindRemoved,
+				ControlPlaneReady:           ChangeKindReason,
+				InfrastructureReady:         ChangeKindSeverity,
+				capi.MachinesReadyCondition: ChangeKindAdded,
+			},
+		},
+		{
+			name:          "case 2: No changes are returned for the same object",
+			before:        oldCluster,
+			after:         oldCluster,
+			expectedKinds: map[capi.ConditionType]ChangeKind{},
+		},
+		{
+			name:   "case 3: All conditions are added when old object is nil",
+			before: (*capi.Cluster)(nil),
+			after:  clusterWith(Creating, corev1.ConditionTrue),
+			expectedKinds: map[capi.ConditionType]ChangeKind{
+				Creating: ChangeKindAdded,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			changes := Diff(tc.before, tc.after, tc.options...)
+
+			if len(changes) != len(tc.expectedKinds) {
+				t.Fatalf("expected %d changes, got %d:\n%s", len(tc.expectedKinds), len(changes), changes)
+			}
+			for conditionType, expectedKind := range tc.expectedKinds {
+				change, ok := changes.Get(conditionType)
+				if !ok || change.Kind != expectedKind {
+					t.Logf("expected %s change for %s, got:\n%s", expectedKind, conditionType, changes)
+					t.Fail()
+				}
+			}
+			for i := 1; i < len(changes); i++ {
+				if changes[i-1].Type > changes[i].Type {
+					t.Logf("expected changes to be ordered by condition type, got:\n%s", changes)
+					t.Fail()
+				}
+			}
+		})
+	}
+}
+
+func TestConditionChangesString(t *testing.T) {
+	changes := Diff(
+		clusterWith(Creating, corev1.ConditionTrue),
+		&capi.Cluster{
+			Status: capi.ClusterStatus{
+				Conditions: capi.Conditions{
+					{Type: Creating, Status: corev1.ConditionFalse, Reason: CreationCompletedReason, Severity: capi.ConditionSeverityInfo, Message: "Done"},
+					{Type: Upgrading, Status: corev1.ConditionFalse, Reason: UpgradeNotStartedReason},
+				},
+			},
+		})
+
+	expected := "Creating StatusChanged: Status=True -> Status=False, Reason=CreationCompleted, Severity=Info, Message=\"Done\"\n" +
+		"Upgrading added: Status=False, Reason=UpgradeNotStarted"
+	if changes.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, changes.String())
+	}
+
+	if ConditionChanges(nil).String() != "no condition changes" {
+		t.Fatalf("unexpected string for no changes: %q", ConditionChanges(nil).String())
+	}
+}
@@ -2,11 +2,14 @@ package conditions
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/giantswarm/microerror"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
 	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
@@ -54,16 +57,98 @@ func ExpectedStatusErrorMessage(cr Object, conditionType capi.ConditionType, exp
 	return fmt.Sprintf("Expected that condition %s on Object %T has status %s, but got %s", conditionType, cr, expectedStatus, got)
 }
 
+var InvalidLifecycleTransitionError = &microerror.Error{
+	Kind: "InvalidLifecycleTransition",
+}
+
+func InvalidLifecycleTransitionErrorMessage(cr Object, from, to LifecyclePhase) string {
+	return fmt.Sprintf("Lifecycle transition of Object %T from phase %s to phase %s is not allowed", cr, from, to)
+}
+
+// IsInvalidLifecycleTransition asserts InvalidLifecycleTransitionError.
+func IsInvalidLifecycleTransition(err error) bool {
+	return microerror.Cause(err) == InvalidLifecycleTransitionError
+}
+
+var InvalidHistoryError = &microerror.Error{
+	Kind: "InvalidHistory",
+}
+
+func InvalidHistoryErrorMessage(cr metav1.Object, err error) string {
+	return fmt.Sprintf("Annotation %s on Object %T cannot be parsed: %s", HistoryAnnotation, cr, err)
+}
+
+// IsInvalidHistory asserts InvalidHistoryError.
+func IsInvalidHistory(err error) bool {
+	return microerror.Cause(err) == InvalidHistoryError
+}
+
+var WaitTimeoutError = &microerror.Error{
+	Kind: "WaitTimeout",
+}
+
+// IsWaitTimeout asserts WaitTimeoutError.
+func IsWaitTimeout(err error) bool {
+	return microerror.Cause(err) == WaitTimeoutError
+}
+
+var WaitFailedError = &microerror.Error{
+	Kind: "WaitFailed",
+}
+
+// IsWaitFailed asserts WaitFailedError.
+func IsWaitFailed(err error) bool {
+	return microerror.Cause(err) == WaitFailedError
+}
+
+func WaitErrorMessage(key client.ObjectKey, t capi.ConditionType, lastObserved *capi.Condition) string {
+	return fmt.Sprintf("Stopped waiting for Object %s, last observed condition %s: %s", key, t, sprintObservedCondition(lastObserved))
+}
+
 var UnsupportedConditionStatusError = &microerror.Error{
 	Kind: "UnsupportedConditionStatus",
 }
 
 func UnsupportedConditionStatusErrorMessage(cr Object, t capi.ConditionType) string {
 	c := capiconditions.Get(cr, t)
-	return fmt.Sprintf("Unsupported status for condition %s, got %s", t, c.Status)
+	var got string
+	if c != nil {
+		got = string(c.Status)
+	} else {
+		got = conditionNotSet
+	}
+
+	return fmt.Sprintf("Unsupported status for condition %s, got %s", t, got)
 }
 
-// IsInvalidCondition asserts invalidConditionError.
+// IsUnsupportedConditionStatus asserts UnsupportedConditionStatusError.
 func IsUnsupportedConditionStatus(err error) bool {
 	return microerror.Cause(err) == UnsupportedConditionStatusError
 }
+
+var InvalidConditionGraphError = &microerror.Error{
+	Kind: "InvalidConditionGraph",
+}
+
+// IsInvalidConditionGraph asserts InvalidConditionGraphError.
+func IsInvalidConditionGraph(err error) bool {
+	return microerror.Cause(err) == InvalidConditionGraphError
+}
+
+var ConditionGraphCycleError = &microerror.Error{
+	Kind: "ConditionGraphCycle",
+}
+
+func ConditionGraphCycleErrorMessage(cycle []capi.ConditionType) string {
+	var types []string
+	for _, conditionType := range cycle {
+		types = append(types, string(conditionType))
+	}
+
+	return fmt.Sprintf("Condition graph has a cycle %s", strings.Join(types, " -> "))
+}
+
+// IsConditionGraphCycle asserts ConditionGraphCycleError.
+func IsConditionGraphCycle(err error) bool {
+	return microerror.Cause(err) == ConditionGraphCycleError
+}
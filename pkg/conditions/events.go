@@ -0,0 +1,78 @@
+package conditions
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// EventRecordingObject wraps an Object and emits a Kubernetes Event on the
+// wrapped object every time a condition status or reason is changed with
+// SetConditions, i.e. with any function from this package or from Cluster
+// API conditions package that sets conditions. Updates that do not change
+// condition status or reason do not emit any events.
+//
+// Event type is Warning when the new condition has status False and severity
+// Warning or Error, otherwise it is Normal. Event reason is the condition
+// type followed by the new status, e.g. ReadyFalse or CreatingTrue.
+//
+// Example:
+//
+//    object := conditions.NewEventRecordingObject(cluster, recorder)
+//    err := conditions.MarkCreationCompleted(object)
+//
+type EventRecordingObject struct {
+	transitionObservingObject
+
+	recorder record.EventRecorder
+}
+
+// NewEventRecordingObject returns a new EventRecordingObject that wraps the
+// specified object and emits events with the specified recorder.
+func NewEventRecordingObject(object Object, recorder record.EventRecorder) *EventRecordingObject {
+	o := &EventRecordingObject{
+		recorder: recorder,
+	}
+	o.transitionObservingObject = newTransitionObservingObject(object, o.recordEvents)
+
+	return o
+}
+
+func (o *EventRecordingObject) recordEvents(transitions ConditionChanges) {
+	for _, change := range transitions {
+		o.recorder.Event(o.Object, eventType(change.After), eventReason(change.After), eventMessage(change))
+	}
+}
+
+func eventType(condition *capi.Condition) string {
+	if IsFalse(condition, WithMinSeverity(capi.ConditionSeverityWarning)) {
+		return corev1.EventTypeWarning
+	}
+
+	return corev1.EventTypeNormal
+}
+
+func eventReason(condition *capi.Condition) string {
+	return fmt.Sprintf("%s%s", condition.Type, condition.Status)
+}
+
+func eventMessage(change ConditionChange) string {
+	var message string
+	if change.Before == nil {
+		message = fmt.Sprintf("Condition %s set to %s", change.Type, change.After.Status)
+	} else {
+		message = fmt.Sprintf("Condition %s changed from %s to %s", change.Type, change.Before.Status, change.After.Status)
+	}
+
+	if change.After.Reason != "" {
+		message += fmt.Sprintf(" with reason %s", change.After.Reason)
+	}
+
+	if change.After.Message != "" {
+		message += fmt.Sprintf(": %s", change.After.Message)
+	}
+
+	return message
+}
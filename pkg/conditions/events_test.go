@@ -0,0 +1,104 @@
+package conditions
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestEventRecordingObject(t *testing.T) {
+	testCases := []struct {
+		name           string
+		object         Object
+		update         func(object Object)
+		expectedEvents []string
+	}{
+		{
+			name:   "case 0: Normal event is emitted when condition is added",
+			object: clusterWithoutConditions(),
+			update: func(object Object) {
+				_ = MarkCreatingTrue(object)
+			},
+			expectedEvents: []string{
+				"Normal CreatingTrue Condition Creating set to True",
+			},
+		},
+		{
+			name:   "case 1: Normal event is emitted when condition status is changed",
+			object: clusterWith(Creating, corev1.ConditionTrue),
+			update: func(object Object) {
+				_ = MarkCreationCompleted(object)
+			},
+			expectedEvents: []string{
+				"Normal CreatingFalse Condition Creating changed from True to False with reason CreationCompleted: Creation has been completed",
+			},
+		},
+		{
+			name:   "case 2: Warning event is emitted when condition has severity Warning",
+			object: clusterWith(capi.ReadyCondition, corev1.ConditionTrue),
+			update: func(object Object) {
+				capiconditions.MarkFalse(object, capi.ReadyCondition, "NodesNotReady", capi.ConditionSeverityWarning, "Nodes are %s", "gone")
+			},
+			expectedEvents: []string{
+				"Warning ReadyFalse Condition Ready changed from True to False with reason NodesNotReady: Nodes are gone",
+			},
+		},
+		{
+			name:   "case 3: Event is emitted when condition reason is changed",
+			object: clusterWithCreatingFalseAnd(corev1.ConditionFalse, UpgradeNotStartedReason),
+			update: func(object Object) {
+				_ = MarkUpgradePending(object)
+			},
+			expectedEvents: []string{
+				"Normal UpgradingFalse Condition Upgrading changed from False to False with reason UpgradePending: Upgrade is pending",
+			},
+		},
+		{
+			name:   "case 4: No event is emitted when condition is not changed",
+			object: clusterWith(Creating, corev1.ConditionTrue),
+			update: func(object Object) {
+				_ = MarkCreatingTrue(object)
+				_ = MarkCreatingTrue(object)
+			},
+		},
+		{
+			name:   "case 5: No event is emitted when only condition message is changed",
+			object: clusterWithoutConditions(),
+			update: func(object Object) {
+				capiconditions.MarkFalse(object, NodePoolsReady, NodePoolsNotReadyReason, capi.ConditionSeverityInfo, "0 of 1")
+				capiconditions.MarkFalse(object, NodePoolsReady, NodePoolsNotReadyReason, capi.ConditionSeverityInfo, "0 of 2")
+			},
+			expectedEvents: []string{
+				"Normal NodePoolsReadyFalse Condition NodePoolsReady set to False with reason NodePoolsNotReady: 0 of 1",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+			recorder := record.NewFakeRecorder(10)
+			object := NewEventRecordingObject(tc.object, recorder)
+
+			tc.update(object)
+			close(recorder.Events)
+
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+
+			if len(events) != len(tc.expectedEvents) {
+				t.Fatalf("expected events %q, got %q", tc.expectedEvents, events)
+			}
+			for i := range events {
+				if events[i] != tc.expectedEvents[i] {
+					t.Fatalf("expected events %q, got %q", tc.expectedEvents, events)
+				}
+			}
+		})
+	}
+}
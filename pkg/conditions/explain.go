@@ -0,0 +1,319 @@
+package conditions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// ExplainObjects contains a cluster and its related objects whose conditions
+// are used by Explain. All objects except Cluster are optional, and provider
+// objects that are not available as typed objects can be set with
+// NewUnstructuredObject.
+type ExplainObjects struct {
+	// Cluster is the explained cluster.
+	Cluster *capi.Cluster
+
+	// InfrastructureObject is the object referenced by Cluster
+	// Spec.InfrastructureRef, e.g. AzureCluster.
+	InfrastructureObject Object
+
+	// ControlPlaneObject is the object referenced by Cluster
+	// Spec.ControlPlaneRef, e.g. KubeadmControlPlane.
+	ControlPlaneObject Object
+
+	// MachinePools and MachineDeployments are node pools of the cluster.
+	// Node pools from other clusters are ignored.
+	MachinePools       []capiexp.MachinePool
+	MachineDeployments []capi.MachineDeployment
+
+	// MachinePoolInfrastructureObjects are objects referenced by
+	// MachinePool Spec.Template.Spec.InfrastructureRef, e.g.
+	// AzureMachinePool. They are matched to MachinePools by kind, namespace
+	// and name.
+	MachinePoolInfrastructureObjects []Object
+}
+
+// ExplanationStep is a single failing condition in the causal chain returned
+// by Explain.
+type ExplanationStep struct {
+	// Kind, Namespace and Name identify the object on which the condition
+	// is set.
+	Kind      string
+	Namespace string
+	Name      string
+
+	// Condition is the failing condition.
+	Condition capi.Condition
+
+	// FailingFor is the time passed since the condition LastTransitionTime,
+	// or 0 when LastTransitionTime is not set.
+	FailingFor time.Duration
+}
+
+// String returns a human readable description of the step.
+func (s ExplanationStep) String() string {
+	text := fmt.Sprintf("%s %s/%s %s (%s)", s.Kind, s.Namespace, s.Name, s.Condition.Type, sprintConditionState(&s.Condition))
+	if s.FailingFor > 0 {
+		text += fmt.Sprintf(" for %s", s.FailingFor)
+	}
+
+	return text
+}
+
+// Explanation is a causal chain of failing conditions, starting with the
+// cluster Ready condition and ending with the deepest failing condition that
+// caused it, i.e. the root cause.
+type Explanation []ExplanationStep
+
+// RootCause returns the last step of the explanation, or false when the
+// explanation is empty.
+func (e Explanation) RootCause() (ExplanationStep, bool) {
+	if len(e) == 0 {
+		return ExplanationStep{}, false
+	}
+
+	return e[len(e)-1], true
+}
+
+// String returns a human readable description of the explanation, with one
+// step per line.
+func (e Explanation) String() string {
+	var lines []string
+	for i, step := range e {
+		if i == 0 {
+			lines = append(lines, step.String())
+		} else {
+			lines = append(lines, fmt.Sprintf("caused by %s", step))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Explain returns the causal chain of failing conditions for the cluster
+// whose Ready condition is set with status False. It returns nil when
+// Cluster is nil or its Ready condition does not have status False.
+//
+// Starting with cluster Ready, every next step is found by following
+// relationships between conditions that are set by this package:
+//
+//   - Ready is a summary of other conditions on the same object (see
+//     ReadySummary), so it is caused by one of the other False conditions
+//     on the object. The condition with the same reason as Ready is used,
+//     and if there is none, the most severe one. Creating and Upgrading
+//     are not considered, since they are False when an operation is
+//     completed.
+//   - InfrastructureReady and ControlPlaneReady mirror Ready condition from
+//     the referenced object (see UpdateInfrastructureReady and
+//     UpdateControlPlaneReady), so they are caused by the referenced object
+//     Ready condition, when it is False.
+//   - NodePoolsReady aggregates node pool conditions (see
+//     UpdateNodePoolsReady), so it is caused by the most severe False
+//     condition of all node pools, which is ReplicasReady for MachinePools
+//     when it is False, or otherwise Ready.
+//
+// The chain ends when the next condition is not False or the related object
+// is not available. Current time is taken from the clock set with
+// WithClock.
+//
+// Example:
+//
+//    explanation := conditions.Explain(conditions.ExplainObjects{
+//        Cluster:              cluster,
+//        InfrastructureObject: azureCluster,
+//        MachinePools:         machinePools.Items,
+//    })
+//    if rootCause, ok := explanation.RootCause(); ok {
+//        logger.Debugf(ctx, "cluster is not ready because of %s", rootCause)
+//    }
+//
+func Explain(objects ExplainObjects, options ...TimeOption) Explanation {
+	if objects.Cluster == nil || !IsFalse(capiconditions.Get(objects.Cluster, capi.ReadyCondition)) {
+		return nil
+	}
+
+	e := explainer{
+		objects: objects,
+		now:     clockOf(options).Now(),
+		visited: map[explainedCondition]bool{},
+	}
+
+	return e.explain(objects.Cluster, capi.ReadyCondition)
+}
+
+// explainedCondition identifies a condition on an object, so that the same
+// condition is not visited twice.
+type explainedCondition struct {
+	object        Object
+	conditionType capi.ConditionType
+}
+
+type explainer struct {
+	objects ExplainObjects
+	now     time.Time
+	visited map[explainedCondition]bool
+}
+
+func (e *explainer) explain(object Object, conditionType capi.ConditionType) Explanation {
+	var explanation Explanation
+	for !isNilGetter(object) {
+		current := explainedCondition{object: object, conditionType: conditionType}
+		condition := capiconditions.Get(object, conditionType)
+		if e.visited[current] || !IsFalse(condition) {
+			break
+		}
+		e.visited[current] = true
+
+		step := ExplanationStep{
+			Kind:      KindOf(object),
+			Namespace: object.GetNamespace(),
+			Name:      object.GetName(),
+			Condition: *condition,
+		}
+		if !condition.LastTransitionTime.IsZero() {
+			step.FailingFor = e.now.Sub(condition.LastTransitionTime.Time)
+		}
+		explanation = append(explanation, step)
+
+		object, conditionType = e.cause(object, condition)
+	}
+
+	return explanation
+}
+
+// cause returns the object and the condition type that caused the specified
+// condition, or nil object when the cause is not known.
+func (e *explainer) cause(object Object, condition *capi.Condition) (Object, capi.ConditionType) {
+	cluster := e.objects.Cluster
+
+	switch {
+	case object == Object(cluster) && condition.Type == InfrastructureReady:
+		return e.objects.InfrastructureObject, capi.ReadyCondition
+	case object == Object(cluster) && condition.Type == ControlPlaneReady:
+		return e.objects.ControlPlaneObject, capi.ReadyCondition
+	case object == Object(cluster) && condition.Type == NodePoolsReady:
+		return e.nodePoolCause()
+	case condition.Type == InfrastructureReady:
+		if machinePool, ok := object.(*capiexp.MachinePool); ok {
+			return e.machinePoolInfrastructureObject(machinePool), capi.ReadyCondition
+		}
+	case condition.Type == capi.ReadyCondition:
+		return e.summaryCause(object, condition)
+	}
+
+	return nil, ""
+}
+
+// summaryCause returns the False condition on the object that caused its
+// Ready condition.
+func (e *explainer) summaryCause(object Object, ready *capi.Condition) (Object, capi.ConditionType) {
+	var candidates []explainCandidate
+	conditions := object.GetConditions()
+	for i := range conditions {
+		condition := &conditions[i]
+		switch condition.Type {
+		case capi.ReadyCondition, Creating, Upgrading:
+			continue
+		}
+
+		candidates = append(candidates, explainCandidate{object: object, condition: condition})
+	}
+
+	cause, ok := mostLikelyCause(candidates, ready.Reason)
+	if !ok {
+		return nil, ""
+	}
+
+	return cause.object, cause.condition.Type
+}
+
+// nodePoolCause returns the node pool condition that caused cluster
+// NodePoolsReady condition, in the same way as UpdateNodePoolsReady
+// aggregates node pool conditions.
+func (e *explainer) nodePoolCause() (Object, capi.ConditionType) {
+	var candidates []explainCandidate
+	for i := range e.objects.MachinePools {
+		machinePool := &e.objects.MachinePools[i]
+		if !isNodePoolOf(e.objects.Cluster, machinePool, machinePool.Spec.ClusterName) {
+			continue
+		}
+
+		condition := capiconditions.Get(machinePool, capiexp.ReplicasReadyCondition)
+		if !IsFalse(condition) {
+			condition = capiconditions.Get(machinePool, capi.ReadyCondition)
+		}
+		candidates = append(candidates, explainCandidate{object: machinePool, condition: condition})
+	}
+	for i := range e.objects.MachineDeployments {
+		machineDeployment := &e.objects.MachineDeployments[i]
+		if !isNodePoolOf(e.objects.Cluster, machineDeployment, machineDeployment.Spec.ClusterName) {
+			continue
+		}
+
+		candidates = append(candidates, explainCandidate{object: machineDeployment, condition: capiconditions.Get(machineDeployment, capi.ReadyCondition)})
+	}
+
+	cause, ok := mostLikelyCause(candidates, "")
+	if !ok {
+		return nil, ""
+	}
+
+	return cause.object, cause.condition.Type
+}
+
+// machinePoolInfrastructureObject returns the object referenced by
+// MachinePool Spec.Template.Spec.InfrastructureRef. When the reference does
+// not have namespace set, the object is in the MachinePool namespace.
+func (e *explainer) machinePoolInfrastructureObject(machinePool *capiexp.MachinePool) Object {
+	reference := machinePool.Spec.Template.Spec.InfrastructureRef
+	namespace := reference.Namespace
+	if namespace == "" {
+		namespace = machinePool.Namespace
+	}
+
+	for _, object := range e.objects.MachinePoolInfrastructureObjects {
+		if isNilGetter(object) {
+			continue
+		}
+		if object.GetNamespace() != namespace {
+			continue
+		}
+		if KindOf(object) == reference.Kind && object.GetName() == reference.Name {
+			return object
+		}
+	}
+
+	return nil
+}
+
+type explainCandidate struct {
+	object    Object
+	condition *capi.Condition
+}
+
+// mostLikelyCause returns the False condition with the specified reason, or
+// if there is none, the most severe False condition. When multiple
+// conditions have the same severity, the first one is returned.
+func mostLikelyCause(candidates []explainCandidate, reason string) (explainCandidate, bool) {
+	var cause explainCandidate
+	found := false
+	for _, candidate := range candidates {
+		if !IsFalse(candidate.condition) {
+			continue
+		}
+		if reason != "" && candidate.condition.Reason == reason {
+			return candidate, true
+		}
+		if !found || severityRank(candidate.condition.Severity) > severityRank(cause.condition.Severity) {
+			cause = candidate
+			found = true
+		}
+	}
+
+	return cause, found
+}
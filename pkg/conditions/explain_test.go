@@ -0,0 +1,263 @@
+package conditions
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+)
+
+func TestExplain(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	minutesAgo := func(minutes int) metav1.Time {
+		return metav1.NewTime(now.Add(-time.Duration(minutes) * time.Minute))
+	}
+
+	newCluster := func(conditions ...capi.Condition) *capi.Cluster {
+		return &capi.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "org-test", Name: "test"},
+			Spec: capi.ClusterSpec{
+				InfrastructureRef: &corev1.ObjectReference{Kind: "AzureCluster", Name: "test"},
+			},
+			Status: capi.ClusterStatus{Conditions: conditions},
+		}
+	}
+	newMachinePool := func(name string, conditions ...capi.Condition) capiexp.MachinePool {
+		machinePool := capiexp.MachinePool{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "org-test", Name: name},
+			Spec:       capiexp.MachinePoolSpec{ClusterName: "test"},
+			Status:     capiexp.MachinePoolStatus{Conditions: conditions},
+		}
+		machinePool.Spec.Template.Spec.InfrastructureRef = corev1.ObjectReference{Kind: "AzureMachinePool", Name: name}
+
+		return machinePool
+	}
+	newProviderObject := func(kind, name string, conditions ...interface{}) Object {
+		u := &unstructured.Unstructured{}
+		u.SetKind(kind)
+		u.SetNamespace("org-test")
+		u.SetName(name)
+		_ = unstructured.SetNestedSlice(u.Object, conditions, "status", "conditions")
+
+		return NewUnstructuredObject(u)
+	}
+
+	clusterReadyFalse := func(reason string) capi.Condition {
+		return capi.Condition{Type: capi.ReadyCondition, Status: corev1.ConditionFalse, Reason: reason, Severity: capi.ConditionSeverityWarning, LastTransitionTime: minutesAgo(5)}
+	}
+	creatingCompleted := capi.Condition{Type: Creating, Status: corev1.ConditionFalse, Reason: CreationCompletedReason, LastTransitionTime: minutesAgo(60)}
+	nodePoolsNotReady := capi.Condition{Type: NodePoolsReady, Status: corev1.ConditionFalse, Reason: NodePoolsNotReadyReason, Severity: capi.ConditionSeverityWarning, LastTransitionTime: minutesAgo(5)}
+	infrastructureNotReady := capi.Condition{Type: InfrastructureReady, Status: corev1.ConditionFalse, Reason: "NetworkNotReady", Severity: capi.ConditionSeverityError, LastTransitionTime: minutesAgo(10)}
+	replicasNotReady := capi.Condition{Type: capiexp.ReplicasReadyCondition, Status: corev1.ConditionFalse, Reason: capiexp.WaitingForReplicasReadyReason, Severity: capi.ConditionSeverityWarning, Message: "0 of 3 replicas are ready", LastTransitionTime: minutesAgo(30)}
+	readyTrue := capi.Condition{Type: capi.ReadyCondition, Status: corev1.ConditionTrue}
+
+	testCases := []struct {
+		name          string
+		objects       ExplainObjects
+		expectedChain []string
+		expectedRoot  time.Duration
+	}{
+		{
+			name: "case 0: Chain goes through node pools to MachinePool ReplicasReady",
+			objects: ExplainObjects{
+				Cluster: newCluster(clusterReadyFalse(NodePoolsNotReadyReason), creatingCompleted, nodePoolsNotReady),
+				MachinePools: []capiexp.MachinePool{
+					newMachinePool("np1", readyTrue),
+					newMachinePool("np2", clusterReadyFalse(capiexp.WaitingForReplicasReadyReason), replicasNotReady),
+				},
+			},
+			expectedChain: []string{
+				"Cluster org-test/test Ready",
+				"Cluster org-test/test NodePoolsReady",
+				"MachinePool org-test/np2 ReplicasReady",
+			},
+			expectedRoot: 30 * time.Minute,
+		},
+		{
+			name: "case 1: Chain goes through mirrored condition to provider object summary",
+			objects: ExplainObjects{
+				Cluster: newCluster(clusterReadyFalse("NetworkNotReady"), creatingCompleted, nodePoolsNotReady, infrastructureNotReady),
+				InfrastructureObject: newProviderObject("AzureCluster", "test",
+					map[string]interface{}{"type": "Ready", "status": "False", "reason": "NetworkNotReady", "severity": "Error"},
+					map[string]interface{}{"type": "VNetReady", "status": "False", "reason": "Throttled", "severity": "Error"},
+					map[string]interface{}{"type": "NetworkReady", "status": "False", "reason": "NetworkNotReady", "severity": "Error", "lastTransitionTime": "2021-01-01T11:45:00Z"},
+				),
+			},
+			expectedChain: []string{
+				"Cluster org-test/test Ready",
+				"Cluster org-test/test InfrastructureReady",
+				"AzureCluster org-test/test Ready",
+				"AzureCluster org-test/test NetworkReady",
+			},
+			expectedRoot: 15 * time.Minute,
+		},
+		{
+			name: "case 2: Most severe condition is followed when no condition has Ready reason",
+			objects: ExplainObjects{
+				Cluster: newCluster(clusterReadyFalse("SomethingFailed"), creatingCompleted, nodePoolsNotReady, infrastructureNotReady),
+			},
+			expectedChain: []string{
+				"Cluster org-test/test Ready",
+				"Cluster org-test/test InfrastructureReady",
+			},
+			expectedRoot: 10 * time.Minute,
+		},
+		{
+			name: "case 3: Chain goes through MachinePool InfrastructureReady to provider object",
+			objects: ExplainObjects{
+				Cluster: newCluster(clusterReadyFalse(NodePoolsNotReadyReason), nodePoolsNotReady),
+				MachinePools: []capiexp.MachinePool{
+					newMachinePool("np1", clusterReadyFalse("NetworkNotReady"), infrastructureNotReady),
+				},
+				MachinePoolInfrastructureObjects: []Object{
+					newProviderObject("AzureMachinePool", "np0",
+						map[string]interface{}{"type": "Ready", "status": "True"}),
+					newProviderObject("AzureMachinePool", "np1",
+						map[string]interface{}{"type": "Ready", "status": "False", "reason": "NetworkNotReady", "severity": "Error", "message": "subnet is full"}),
+				},
+			},
+			expectedChain: []string{
+				"Cluster org-test/test Ready",
+				"Cluster org-test/test NodePoolsReady",
+				"MachinePool org-test/np1 Ready",
+				"MachinePool org-test/np1 InfrastructureReady",
+				"AzureMachinePool org-test/np1 Ready",
+			},
+		},
+		{
+			name: "case 4: Chain ends when referenced object is not available",
+			objects: ExplainObjects{
+				Cluster:              newCluster(clusterReadyFalse("NetworkNotReady"), infrastructureNotReady),
+				InfrastructureObject: (*capi.Cluster)(nil),
+			},
+			expectedChain: []string{
+				"Cluster org-test/test Ready",
+				"Cluster org-test/test InfrastructureReady",
+			},
+			expectedRoot: 10 * time.Minute,
+		},
+		{
+			name: "case 5: Node pools from other clusters are ignored",
+			objects: ExplainObjects{
+				Cluster: newCluster(clusterReadyFalse(NodePoolsNotReadyReason), nodePoolsNotReady),
+				MachinePools: []capiexp.MachinePool{
+					func() capiexp.MachinePool {
+						machinePool := newMachinePool("np1", replicasNotReady)
+						machinePool.Spec.ClusterName = "other"
+						return machinePool
+					}(),
+				},
+			},
+			expectedChain: []string{
+				"Cluster org-test/test Ready",
+				"Cluster org-test/test NodePoolsReady",
+			},
+			expectedRoot: 5 * time.Minute,
+		},
+		{
+			name: "case 6: Node pools of a cluster with the same name in another namespace are ignored",
+			objects: ExplainObjects{
+				Cluster: newCluster(clusterReadyFalse(NodePoolsNotReadyReason), nodePoolsNotReady),
+				MachinePools: []capiexp.MachinePool{
+					func() capiexp.MachinePool {
+						machinePool := newMachinePool("np1", replicasNotReady)
+						machinePool.Namespace = "org-other"
+						return machinePool
+					}(),
+				},
+			},
+			expectedChain: []string{
+				"Cluster org-test/test Ready",
+				"Cluster org-test/test NodePoolsReady",
+			},
+			expectedRoot: 5 * time.Minute,
+		},
+		{
+			name: "case 7: MachinePool infrastructure object with the same name in another namespace is ignored",
+			objects: ExplainObjects{
+				Cluster: newCluster(clusterReadyFalse(NodePoolsNotReadyReason), nodePoolsNotReady),
+				MachinePools: []capiexp.MachinePool{
+					newMachinePool("np1", clusterReadyFalse("NetworkNotReady"), infrastructureNotReady),
+				},
+				MachinePoolInfrastructureObjects: []Object{
+					func() Object {
+						object := newProviderObject("AzureMachinePool", "np1",
+							map[string]interface{}{"type": "Ready", "status": "False", "reason": "NetworkNotReady", "severity": "Error", "message": "subnet is full"})
+						object.SetNamespace("org-other")
+						return object
+					}(),
+					newProviderObject("AzureMachinePool", "np1",
+						map[string]interface{}{"type": "Ready", "status": "True"}),
+				},
+			},
+			expectedChain: []string{
+				"Cluster org-test/test Ready",
+				"Cluster org-test/test NodePoolsReady",
+				"MachinePool org-test/np1 Ready",
+				"MachinePool org-test/np1 InfrastructureReady",
+			},
+			expectedRoot: 10 * time.Minute,
+		},
+		{
+			name:    "case 8: Nothing is explained for Ready cluster",
+			objects: ExplainObjects{Cluster: newCluster(readyTrue, nodePoolsNotReady)},
+		},
+		{
+			name:    "case 9: Nothing is explained for nil cluster",
+			objects: ExplainObjects{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			explanation := Explain(tc.objects, WithClock(NewFakeClock(now)))
+
+			var chain []string
+			for _, step := range explanation {
+				chain = append(chain, step.Kind+" "+step.Namespace+"/"+step.Name+" "+string(step.Condition.Type))
+			}
+			if strings.Join(chain, "\n") != strings.Join(tc.expectedChain, "\n") {
+				t.Fatalf("expected chain:\n%s\ngot:\n%s", strings.Join(tc.expectedChain, "\n"), explanation)
+			}
+
+			rootCause, ok := explanation.RootCause()
+			if ok != (len(tc.expectedChain) > 0) {
+				t.Fatalf("expected root cause %t, got %t", len(tc.expectedChain) > 0, ok)
+			}
+			if rootCause.FailingFor != tc.expectedRoot {
+				t.Fatalf("expected root cause failing for %s, got %s", tc.expectedRoot, rootCause.FailingFor)
+			}
+		})
+	}
+}
+
+func TestExplanationString(t *testing.T) {
+	explanation := Explanation{
+		{
+			Kind:       "Cluster",
+			Namespace:  "org-test",
+			Name:       "test",
+			Condition:  capi.Condition{Type: capi.ReadyCondition, Status: corev1.ConditionFalse, Reason: NodePoolsNotReadyReason, Severity: capi.ConditionSeverityWarning},
+			FailingFor: 5 * time.Minute,
+		},
+		{
+			Kind:      "MachinePool",
+			Namespace: "org-test",
+			Name:      "np1",
+			Condition: capi.Condition{Type: capiexp.ReplicasReadyCondition, Status: corev1.ConditionFalse, Reason: capiexp.WaitingForReplicasReadyReason, Message: "0 of 3 replicas are ready"},
+		},
+	}
+
+	expected := "Cluster org-test/test Ready (Status=False, Reason=NodePoolsNotReady, Severity=Warning) for 5m0s\n" +
+		"caused by MachinePool org-test/np1 ReplicasReady (Status=False, Reason=WaitingForReplicasReady, Message=\"0 of 3 replicas are ready\")"
+	if explanation.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, explanation.String())
+	}
+}
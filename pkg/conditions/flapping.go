@@ -0,0 +1,315 @@
+package conditions
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	// FlappingReason is a condition reason that is set by
+	// SetConditionDampened when the condition is flapping, i.e. its status
+	// is changing too often, and the previous condition status False or
+	// Unknown is kept until the condition is stable again.
+	FlappingReason = "Flapping"
+
+	// DefaultFlappingWindow is the default duration of the sliding window
+	// in which condition status changes are counted.
+	DefaultFlappingWindow = 15 * time.Minute
+
+	// DefaultFlappingThreshold is the default number of condition status
+	// changes in the sliding window from which the condition is considered
+	// to be flapping.
+	DefaultFlappingThreshold = 4
+)
+
+type flappingOptions struct {
+	timeOptions
+
+	window    time.Duration
+	threshold int
+}
+
+// FlappingOption is an option for IsFlapping and FlapDetector. TimeOption
+// can be used as FlappingOption too, e.g. WithClock sets the clock used to
+// determine the sliding window.
+type FlappingOption interface {
+	applyToFlapping(options *flappingOptions)
+}
+
+type flappingOptionFunc func(options *flappingOptions)
+
+func (f flappingOptionFunc) applyToFlapping(options *flappingOptions) {
+	f(options)
+}
+
+func (o TimeOption) applyToFlapping(options *flappingOptions) {
+	o(&options.timeOptions)
+}
+
+// WithFlappingWindow returns a FlappingOption that sets the duration of the
+// sliding window in which condition status changes are counted. Default is
+// DefaultFlappingWindow.
+func WithFlappingWindow(window time.Duration) FlappingOption {
+	return flappingOptionFunc(func(options *flappingOptions) {
+		options.window = window
+	})
+}
+
+// WithFlappingThreshold returns a FlappingOption that sets the number of
+// condition status changes in the sliding window from which the condition
+// is considered to be flapping. Default is DefaultFlappingThreshold.
+func WithFlappingThreshold(threshold int) FlappingOption {
+	return flappingOptionFunc(func(options *flappingOptions) {
+		options.threshold = threshold
+	})
+}
+
+func newFlappingOptions(options ...FlappingOption) flappingOptions {
+	flappingOpts := flappingOptions{
+		window:    DefaultFlappingWindow,
+		threshold: DefaultFlappingThreshold,
+	}
+	for _, option := range options {
+		option.applyToFlapping(&flappingOpts)
+	}
+
+	return flappingOpts
+}
+
+func (o flappingOptions) isFlapping(changes int) bool {
+	return o.threshold > 0 && changes >= o.threshold
+}
+
+// IsFlapping checks if the condition with the specified type is flapping
+// according to the specified history, i.e. if its status has changed at
+// least as many times as the flapping threshold within the flapping window.
+// Reason changes without status change are not counted. Current time is
+// taken from the clock set with WithClock.
+//
+// Example:
+//
+//    history, err := conditions.GetHistory(machinePool)
+//    if err != nil {
+//        return microerror.Mask(err)
+//    }
+//    if conditions.IsFlapping(history, capiexp.ReplicasReadyCondition, conditions.WithFlappingThreshold(6)) {
+//        // ...
+//    }
+//
+func IsFlapping(history History, conditionType capi.ConditionType, options ...FlappingOption) bool {
+	flappingOpts := newFlappingOptions(options...)
+	windowStart := flappingOpts.clockOrDefault().Now().Add(-flappingOpts.window)
+
+	changes := 0
+	var previous *HistoryEntry
+	for _, entry := range history.ForType(conditionType) {
+		entry := entry
+		if previous != nil && previous.Status != entry.Status && !entry.Time.Time.Before(windowStart) {
+			changes++
+		}
+		previous = &entry
+	}
+
+	return flappingOpts.isFlapping(changes)
+}
+
+// FlapDetector detects flapping conditions from successive observations,
+// e.g. from conditions computed in every reconciliation loop. As opposed to
+// IsFlapping, it does not need condition history, and it can observe
+// computed condition status before it is written, so it keeps detecting
+// flapping also while writes are dampened. FlapDetector is safe for
+// concurrent use, so a single FlapDetector can be shared by all workers of
+// a controller.
+//
+// Example:
+//
+//    // In controller setup.
+//    r.flapDetector = conditions.NewFlapDetector(conditions.WithFlappingWindow(10 * time.Minute))
+//
+//    // In reconciliation loop.
+//    replicasReady := computeReplicasReady(machinePool)
+//    r.flapDetector.SetCondition(machinePool, replicasReady)
+//
+type FlapDetector struct {
+	options flappingOptions
+
+	mutex        sync.Mutex
+	observations map[conditionKey]*flapObservations
+}
+
+// conditionKey identifies a condition on an object across reconciliation
+// loops, in which the object is fetched again.
+type conditionKey struct {
+	kind          string
+	namespace     string
+	name          string
+	conditionType capi.ConditionType
+}
+
+type flapObservations struct {
+	status  corev1.ConditionStatus
+	changes []time.Time
+}
+
+// NewFlapDetector returns a new FlapDetector.
+func NewFlapDetector(options ...FlappingOption) *FlapDetector {
+	return &FlapDetector{
+		options:      newFlappingOptions(options...),
+		observations: map[conditionKey]*flapObservations{},
+	}
+}
+
+// Observe records the observed status of the condition with the specified
+// type on the specified object and returns true if the condition is
+// flapping. Objects are identified by kind, namespace and name. Condition
+// that is not set is observed as status Unknown.
+func (d *FlapDetector) Observe(object Object, conditionType capi.ConditionType) bool {
+	status := corev1.ConditionUnknown
+	if condition := capiconditions.Get(object, conditionType); condition != nil {
+		status = condition.Status
+	}
+
+	return d.observe(conditionKeyOf(object, conditionType), status)
+}
+
+// IsFlapping checks if the condition with the specified type on the
+// specified object is flapping according to earlier observations.
+func (d *FlapDetector) IsFlapping(object Object, conditionType capi.ConditionType) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	observations, ok := d.observations[conditionKeyOf(object, conditionType)]
+	if !ok {
+		return false
+	}
+	d.prune(observations)
+
+	return d.options.isFlapping(len(observations.changes))
+}
+
+// Forget removes all observations for the specified object, e.g. when the
+// object is deleted.
+func (d *FlapDetector) Forget(object Object) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	key := conditionKeyOf(object, "")
+	for k := range d.observations {
+		if k.kind == key.kind && k.namespace == key.namespace && k.name == key.name {
+			delete(d.observations, k)
+		}
+	}
+}
+
+// SetCondition observes the status of the specified condition and sets it
+// on the object with SetConditionDampened, so that the previous status is
+// kept while the condition is flapping. It returns true if the condition
+// was dampened.
+func (d *FlapDetector) SetCondition(object Object, condition capi.Condition) bool {
+	flapping := d.observe(conditionKeyOf(object, condition.Type), condition.Status)
+	return SetConditionDampened(object, condition, flapping, WithClock(d.options.clockOrDefault()))
+}
+
+func (d *FlapDetector) observe(key conditionKey, status corev1.ConditionStatus) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	observations, ok := d.observations[key]
+	if !ok {
+		d.observations[key] = &flapObservations{status: status}
+		return false
+	}
+
+	if observations.status != status {
+		observations.status = status
+		observations.changes = append(observations.changes, d.options.clockOrDefault().Now())
+	}
+	d.prune(observations)
+
+	return d.options.isFlapping(len(observations.changes))
+}
+
+// prune removes status changes that are outside of the sliding window.
+func (d *FlapDetector) prune(observations *flapObservations) {
+	windowStart := d.options.clockOrDefault().Now().Add(-d.options.window)
+
+	i := 0
+	for i < len(observations.changes) && observations.changes[i].Before(windowStart) {
+		i++
+	}
+	observations.changes = observations.changes[i:]
+}
+
+func conditionKeyOf(object Object, conditionType capi.ConditionType) conditionKey {
+	return conditionKey{
+		kind:          KindOf(object),
+		namespace:     object.GetNamespace(),
+		name:          object.GetName(),
+		conditionType: conditionType,
+	}
+}
+
+// SetConditionDampened sets the specified condition on the object, unless
+// the condition is flapping and it is already set on the object. In that
+// case, the current condition status is kept:
+//
+//   - When the kept status is False or Unknown, the condition is set with
+//     reason Flapping and a message that contains the latest computed
+//     status, reason and message. Severity is set to Warning when the kept
+//     status is False. LastTransitionTime is not changed, also not when the
+//     reason changes to Flapping, since status stays the same, so it is the
+//     time since when the condition has its current status. While the
+//     condition is flapping, only the message is updated.
+//   - When the kept status is True, the condition is not changed at all,
+//     since Cluster API conditions with status True do not have a reason.
+//
+// It returns true if the condition was dampened. Current time is taken from
+// the clock set with WithClock.
+//
+// Example:
+//
+//    history, _ := conditions.GetHistory(machinePool)
+//    flapping := conditions.IsFlapping(history, capiexp.ReplicasReadyCondition)
+//    conditions.SetConditionDampened(machinePool, replicasReady, flapping)
+//
+func SetConditionDampened(object Object, condition capi.Condition, flapping bool, options ...TimeOption) bool {
+	clock := clockOf(options)
+
+	current := capiconditions.Get(object, condition.Type)
+	if !flapping || current == nil {
+		setCondition(object, condition, clock)
+		return false
+	}
+
+	if current.Status == corev1.ConditionTrue {
+		return true
+	}
+
+	dampened := capi.Condition{
+		Type:    condition.Type,
+		Status:  current.Status,
+		Reason:  FlappingReason,
+		Message: fmt.Sprintf("Condition is flapping, status %s is kept until it is stable, latest computed condition: %s", current.Status, sprintConditionState(&condition)),
+	}
+	if dampened.Status == corev1.ConditionFalse {
+		dampened.Severity = capi.ConditionSeverityWarning
+	}
+
+	// Condition is replaced directly instead of with setCondition, which
+	// would change LastTransitionTime when the reason changes to Flapping.
+	dampened.LastTransitionTime = current.LastTransitionTime
+	conditions := object.GetConditions()
+	for i := range conditions {
+		if conditions[i].Type == dampened.Type {
+			conditions[i] = dampened
+		}
+	}
+	object.SetConditions(conditions)
+
+	return true
+}
@@ -0,0 +1,225 @@
+package conditions
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestIsFlapping(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	entry := func(status corev1.ConditionStatus, reason string, minutesAgo int) HistoryEntry {
+		return HistoryEntry{
+			Type:   capiexp.ReplicasReadyCondition,
+			Status: status,
+			Reason: reason,
+			Time:   metav1.NewTime(now.Add(-time.Duration(minutesAgo) * time.Minute)),
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		history          History
+		options          []FlappingOption
+		expectedFlapping bool
+	}{
+		{
+			name: "case 0: Condition with status changes reaching threshold is flapping",
+			history: History{
+				entry(corev1.ConditionTrue, "", 12),
+				entry(corev1.ConditionFalse, "ScalingUp", 10),
+				entry(corev1.ConditionTrue, "", 8),
+				entry(corev1.ConditionFalse, "ScalingUp", 6),
+				entry(corev1.ConditionTrue, "", 4),
+			},
+			expectedFlapping: true,
+		},
+		{
+			name: "case 1: Condition with status changes below threshold is stable",
+			history: History{
+				entry(corev1.ConditionTrue, "", 12),
+				entry(corev1.ConditionFalse, "ScalingUp", 10),
+				entry(corev1.ConditionTrue, "", 8),
+				entry(corev1.ConditionFalse, "ScalingUp", 6),
+			},
+			expectedFlapping: false,
+		},
+		{
+			name: "case 2: Status changes outside of window are not counted",
+			history: History{
+				entry(corev1.ConditionTrue, "", 40),
+				entry(corev1.ConditionFalse, "ScalingUp", 35),
+				entry(corev1.ConditionTrue, "", 30),
+				entry(corev1.ConditionFalse, "ScalingUp", 25),
+				entry(corev1.ConditionTrue, "", 4),
+			},
+			expectedFlapping: false,
+		},
+		{
+			name: "case 3: Reason changes are not counted",
+			history: History{
+				entry(corev1.ConditionFalse, "ScalingUp", 12),
+				entry(corev1.ConditionFalse, "ScalingDown", 10),
+				entry(corev1.ConditionFalse, "ScalingUp", 8),
+				entry(corev1.ConditionFalse, "ScalingDown", 6),
+				entry(corev1.ConditionFalse, "ScalingUp", 4),
+			},
+			expectedFlapping: false,
+		},
+		{
+			name: "case 4: Threshold and window can be changed",
+			history: History{
+				entry(corev1.ConditionTrue, "", 40),
+				entry(corev1.ConditionFalse, "ScalingUp", 35),
+				entry(corev1.ConditionTrue, "", 30),
+			},
+			options:          []FlappingOption{WithFlappingThreshold(2), WithFlappingWindow(time.Hour)},
+			expectedFlapping: true,
+		},
+		{
+			name: "case 5: Transitions of other conditions are not counted",
+			history: History{
+				entry(corev1.ConditionTrue, "", 12),
+				{Type: capi.ReadyCondition, Status: corev1.ConditionFalse, Time: metav1.NewTime(now.Add(-10 * time.Minute))},
+				{Type: capi.ReadyCondition, Status: corev1.ConditionTrue, Time: metav1.NewTime(now.Add(-8 * time.Minute))},
+				entry(corev1.ConditionFalse, "ScalingUp", 6),
+			},
+			options:          []FlappingOption{WithFlappingThreshold(2)},
+			expectedFlapping: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			options := append(tc.options, WithClock(NewFakeClock(now)))
+			flapping := IsFlapping(tc.history, capiexp.ReplicasReadyCondition, options...)
+
+			if flapping != tc.expectedFlapping {
+				t.Fatalf("expected flapping %t, got %t", tc.expectedFlapping, flapping)
+			}
+		})
+	}
+}
+
+func TestFlapDetector(t *testing.T) {
+	clock := NewFakeClock(time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC))
+	detector := NewFlapDetector(WithFlappingThreshold(3), WithFlappingWindow(10*time.Minute), WithClock(clock))
+	machinePool := machinePoolWithoutConditions()
+
+	replicasReady := capi.Condition{Type: capiexp.ReplicasReadyCondition, Status: corev1.ConditionTrue}
+	replicasNotReady := capi.Condition{Type: capiexp.ReplicasReadyCondition, Status: corev1.ConditionFalse, Reason: capiexp.WaitingForReplicasReadyReason, Severity: capi.ConditionSeverityInfo}
+
+	// Condition oscillates on every reconciliation. The first three changes
+	// are written, after that the condition is flapping and True is kept
+	// unchanged, without reason.
+	expectedStatuses := []corev1.ConditionStatus{
+		corev1.ConditionTrue,
+		corev1.ConditionFalse,
+		corev1.ConditionTrue,
+		corev1.ConditionTrue,
+		corev1.ConditionTrue,
+		corev1.ConditionTrue,
+	}
+	for i, expectedStatus := range expectedStatuses {
+		condition := replicasReady
+		if i%2 == 1 {
+			condition = replicasNotReady
+		}
+
+		dampened := detector.SetCondition(machinePool, condition)
+
+		current := capiconditions.Get(machinePool, capiexp.ReplicasReadyCondition)
+		if current.Status != expectedStatus {
+			t.Fatalf("observation %d: expected status %s, got %s", i, expectedStatus, sprintCondition(current))
+		}
+		if dampened != (i >= 3) || (dampened && current.Reason != "") {
+			t.Fatalf("observation %d: expected dampened %t, got %t with %s", i, i >= 3, dampened, sprintCondition(current))
+		}
+		clock.Step(time.Minute)
+	}
+
+	if !detector.IsFlapping(machinePool, capiexp.ReplicasReadyCondition) {
+		t.Fatalf("expected condition to be flapping")
+	}
+	lastTransitionTime := capiconditions.GetLastTransitionTime(machinePool, capiexp.ReplicasReadyCondition)
+
+	// After the window, the condition is stable again and it is written.
+	clock.Step(10 * time.Minute)
+	if detector.SetCondition(machinePool, replicasNotReady) {
+		t.Fatalf("expected condition not to be dampened after the window")
+	}
+	if !IsFalse(capiconditions.Get(machinePool, capiexp.ReplicasReadyCondition), WithReason(capiexp.WaitingForReplicasReadyReason)) {
+		t.Fatalf("expected condition to be written, got %s", sprintConditionForObject(machinePool, capiexp.ReplicasReadyCondition))
+	}
+	if capiconditions.GetLastTransitionTime(machinePool, capiexp.ReplicasReadyCondition).Equal(lastTransitionTime) {
+		t.Fatalf("expected LastTransitionTime to change")
+	}
+
+	detector.Forget(machinePool)
+	if detector.IsFlapping(machinePool, capiexp.ReplicasReadyCondition) {
+		t.Fatalf("expected forgotten condition not to be flapping")
+	}
+}
+
+func TestSetConditionDampened(t *testing.T) {
+	clock := NewFakeClock(time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC))
+	machinePool := machinePoolWith(capiexp.ReplicasReadyCondition, corev1.ConditionFalse)
+	condition := capi.Condition{Type: capiexp.ReplicasReadyCondition, Status: corev1.ConditionTrue}
+
+	if !SetConditionDampened(machinePool, condition, true, WithClock(clock)) {
+		t.Fatalf("expected condition to be dampened")
+	}
+	current := capiconditions.Get(machinePool, capiexp.ReplicasReadyCondition)
+	if !IsFalse(current, WithReason(FlappingReason), WithSeverityWarning()) {
+		t.Fatalf("expected status False with reason Flapping, got %s", sprintCondition(current))
+	}
+	if !strings.Contains(current.Message, "Status=True") {
+		t.Fatalf("expected message to contain latest computed status, got %q", current.Message)
+	}
+
+	// LastTransitionTime is kept across the first dampening and while the
+	// condition is flapping.
+	lastTransitionTime := metav1.NewTime(time.Date(2021, 1, 1, 11, 0, 0, 0, time.UTC))
+	machinePool = machinePoolWith(capiexp.ReplicasReadyCondition, corev1.ConditionFalse)
+	machinePool.Status.Conditions[0].Reason = "WaitingForReplicasReady"
+	machinePool.Status.Conditions[0].LastTransitionTime = lastTransitionTime
+	for i := 0; i < 2; i++ {
+		clock.Step(time.Minute)
+		if !SetConditionDampened(machinePool, condition, true, WithClock(clock)) {
+			t.Fatalf("expected condition to be dampened")
+		}
+		current = capiconditions.Get(machinePool, capiexp.ReplicasReadyCondition)
+		if !IsFalse(current, WithReason(FlappingReason)) || !current.LastTransitionTime.Equal(&lastTransitionTime) {
+			t.Fatalf("expected reason Flapping and LastTransitionTime %s, got %s at %s", lastTransitionTime, sprintCondition(current), current.LastTransitionTime)
+		}
+	}
+
+	// Condition with status True is kept unchanged, without reason.
+	machinePool = machinePoolWith(capiexp.ReplicasReadyCondition, corev1.ConditionTrue)
+	machinePool.Status.Conditions[0].LastTransitionTime = lastTransitionTime
+	kept := machinePool.Status.Conditions[0]
+	falseCondition := capi.Condition{Type: capiexp.ReplicasReadyCondition, Status: corev1.ConditionFalse, Reason: "WaitingForReplicasReady", Severity: capi.ConditionSeverityWarning}
+	if !SetConditionDampened(machinePool, falseCondition, true, WithClock(clock)) {
+		t.Fatalf("expected condition to be dampened")
+	}
+	if current = capiconditions.Get(machinePool, capiexp.ReplicasReadyCondition); *current != kept {
+		t.Fatalf("expected condition %s to be kept, got %s", sprintCondition(&kept), sprintCondition(current))
+	}
+
+	// Condition that is not set yet is not dampened.
+	machinePool = machinePoolWithoutConditions()
+	if SetConditionDampened(machinePool, condition, true, WithClock(clock)) {
+		t.Fatalf("expected condition that is not set not to be dampened")
+	}
+	if !IsTrue(capiconditions.Get(machinePool, capiexp.ReplicasReadyCondition)) {
+		t.Fatalf("expected condition to be set, got %s", sprintConditionForObject(machinePool, capiexp.ReplicasReadyCondition))
+	}
+}
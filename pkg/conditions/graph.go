@@ -0,0 +1,192 @@
+package conditions
+
+import (
+	"github.com/giantswarm/microerror"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// ComputeFunc computes a derived condition of the object. Inputs contains
+// current input conditions, i.e. conditions that are derived earlier in the
+// same evaluation or that are already set on the object. Inputs that are not
+// set are nil.
+type ComputeFunc func(object Object, inputs map[capi.ConditionType]*capi.Condition) capi.Condition
+
+// ConditionNode declares a derived condition in ConditionGraph, together
+// with the conditions it depends on and the function that computes it.
+type ConditionNode struct {
+	// Type is the type of the derived condition. Type of the condition
+	// returned by Compute is always set to Type.
+	Type capi.ConditionType
+
+	// Inputs are condition types that the condition depends on. Inputs can
+	// be other derived conditions in the graph, or conditions that are set
+	// by other means, e.g. ReplicasReady set by Cluster API controllers.
+	Inputs []capi.ConditionType
+
+	// Compute computes the condition from its inputs.
+	Compute ComputeFunc
+}
+
+// ConditionGraph is a validated dependency graph of derived conditions,
+// which evaluates all derived conditions in topological order, so that every
+// condition is computed after all of its inputs.
+type ConditionGraph struct {
+	nodes map[capi.ConditionType]ConditionNode
+	order []capi.ConditionType
+}
+
+// NewConditionGraph returns a new ConditionGraph with the specified nodes.
+// It returns InvalidConditionGraphError when a node does not have type or
+// compute function or when multiple nodes have the same type, and
+// ConditionGraphCycleError when a condition depends on itself, directly or
+// through other conditions.
+//
+// Example:
+//
+//    graph, err := conditions.NewConditionGraph(
+//        conditions.ConditionNode{
+//            Type:    conditions.NodePoolsReady,
+//            Inputs:  []capi.ConditionType{capiexp.ReplicasReadyCondition},
+//            Compute: computeNodePoolsReady,
+//        },
+//        conditions.NewReadySummaryNode(conditions.WithIgnoredDuringCreation(conditions.NodePoolsReady)),
+//    )
+//    if err != nil {
+//        return microerror.Mask(err)
+//    }
+//
+//    graph.Evaluate(cluster)
+//
+func NewConditionGraph(nodes ...ConditionNode) (*ConditionGraph, error) {
+	g := &ConditionGraph{
+		nodes: map[capi.ConditionType]ConditionNode{},
+	}
+
+	var declared []capi.ConditionType
+	for _, node := range nodes {
+		if node.Type == "" {
+			return nil, microerror.Maskf(InvalidConditionGraphError, "Condition node type must not be empty")
+		}
+		if node.Compute == nil {
+			return nil, microerror.Maskf(InvalidConditionGraphError, "Condition node %s compute function must not be empty", node.Type)
+		}
+		if _, ok := g.nodes[node.Type]; ok {
+			return nil, microerror.Maskf(InvalidConditionGraphError, "Condition node %s is declared multiple times", node.Type)
+		}
+
+		g.nodes[node.Type] = node
+		declared = append(declared, node.Type)
+	}
+
+	// Topological order is computed with depth-first search, which visits
+	// nodes and their inputs in declaration order, so the order is
+	// deterministic.
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := map[capi.ConditionType]int{}
+	var path []capi.ConditionType
+	var visit func(conditionType capi.ConditionType) error
+	visit = func(conditionType capi.ConditionType) error {
+		switch state[conditionType] {
+		case visited:
+			return nil
+		case visiting:
+			var cycle []capi.ConditionType
+			for i := range path {
+				if path[i] == conditionType {
+					cycle = append(cycle, path[i:]...)
+					break
+				}
+			}
+			cycle = append(cycle, conditionType)
+			return microerror.Maskf(ConditionGraphCycleError, "%s", ConditionGraphCycleErrorMessage(cycle))
+		}
+
+		node, ok := g.nodes[conditionType]
+		if !ok {
+			// Input that is not derived in the graph.
+			return nil
+		}
+
+		state[conditionType] = visiting
+		path = append(path, conditionType)
+		for _, input := range node.Inputs {
+			err := visit(input)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+		path = path[:len(path)-1]
+		state[conditionType] = visited
+		g.order = append(g.order, conditionType)
+
+		return nil
+	}
+
+	for _, conditionType := range declared {
+		err := visit(conditionType)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	return g, nil
+}
+
+// Order returns derived condition types in the order in which they are
+// evaluated.
+func (g *ConditionGraph) Order() []capi.ConditionType {
+	return append([]capi.ConditionType(nil), g.order...)
+}
+
+// Evaluate computes all derived conditions of the object in topological
+// order and returns them in that order. Every computed condition is set on
+// the object before the conditions that depend on it are computed, so
+// compute functions can read inputs either from inputs map or directly from
+// the object (e.g. with ReadySummary). LastTransitionTime is changed only
+// when condition status or reason changes. Current time is taken from the
+// clock set with WithClock.
+func (g *ConditionGraph) Evaluate(object Object, options ...TimeOption) capi.Conditions {
+	clock := clockOf(options)
+
+	var result capi.Conditions
+	for _, conditionType := range g.order {
+		node := g.nodes[conditionType]
+
+		inputs := map[capi.ConditionType]*capi.Condition{}
+		for _, input := range node.Inputs {
+			inputs[input] = capiconditions.Get(object, input)
+		}
+
+		condition := node.Compute(object, inputs)
+		condition.Type = node.Type
+		setCondition(object, condition, clock)
+
+		result = append(result, *capiconditions.Get(object, node.Type))
+	}
+
+	return result
+}
+
+// NewReadySummaryNode returns a ConditionNode for Ready condition that is
+// computed with ReadySummary with the specified options. Its inputs are all
+// summarized conditions, together with Creating and Upgrading.
+func NewReadySummaryNode(options ...ReadySummaryOption) ConditionNode {
+	summaryOpts := newReadySummaryOptions(options...)
+
+	inputs := []capi.ConditionType{Creating, Upgrading}
+	for _, summarized := range summaryOpts.conditions {
+		inputs = append(inputs, summarized.conditionType)
+	}
+
+	return ConditionNode{
+		Type:   capi.ReadyCondition,
+		Inputs: inputs,
+		Compute: func(object Object, _ map[capi.ConditionType]*capi.Condition) capi.Condition {
+			return ReadySummary(object, options...)
+		},
+	}
+}
@@ -0,0 +1,166 @@
+package conditions
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func trueWhenAllTrue(object Object, inputs map[capi.ConditionType]*capi.Condition) capi.Condition {
+	for conditionType, input := range inputs {
+		if !IsTrue(input) {
+			return capi.Condition{Status: corev1.ConditionFalse, Reason: fmt.Sprintf("%sNotTrue", conditionType), Severity: capi.ConditionSeverityWarning}
+		}
+	}
+
+	return capi.Condition{Status: corev1.ConditionTrue}
+}
+
+func TestNewConditionGraph(t *testing.T) {
+	testCases := []struct {
+		name            string
+		nodes           []ConditionNode
+		errorMatcher    func(error) bool
+		expectedOrder   []capi.ConditionType
+		expectedMessage string
+	}{
+		{
+			name: "case 0: Conditions are ordered after their inputs",
+			nodes: []ConditionNode{
+				{Type: capi.ReadyCondition, Inputs: []capi.ConditionType{InfrastructureReady, NodePoolsReady}, Compute: trueWhenAllTrue},
+				{Type: NodePoolsReady, Inputs: []capi.ConditionType{capiexp.ReplicasReadyCondition}, Compute: trueWhenAllTrue},
+			},
+			expectedOrder: []capi.ConditionType{NodePoolsReady, capi.ReadyCondition},
+		},
+		{
+			name: "case 1: Independent conditions keep declaration order",
+			nodes: []ConditionNode{
+				{Type: ControlPlaneReady, Compute: trueWhenAllTrue},
+				{Type: NodePoolsReady, Compute: trueWhenAllTrue},
+				{Type: InfrastructureReady, Compute: trueWhenAllTrue},
+			},
+			expectedOrder: []capi.ConditionType{ControlPlaneReady, NodePoolsReady, InfrastructureReady},
+		},
+		{
+			name: "case 2: Cycle is detected",
+			nodes: []ConditionNode{
+				{Type: capi.ReadyCondition, Inputs: []capi.ConditionType{NodePoolsReady}, Compute: trueWhenAllTrue},
+				{Type: NodePoolsReady, Inputs: []capi.ConditionType{capiexp.ReplicasReadyCondition}, Compute: trueWhenAllTrue},
+				{Type: capiexp.ReplicasReadyCondition, Inputs: []capi.ConditionType{NodePoolsReady}, Compute: trueWhenAllTrue},
+			},
+			errorMatcher:    IsConditionGraphCycle,
+			expectedMessage: "NodePoolsReady -> ReplicasReady -> NodePoolsReady",
+		},
+		{
+			name: "case 3: Condition that depends on itself is a cycle",
+			nodes: []ConditionNode{
+				{Type: capi.ReadyCondition, Inputs: []capi.ConditionType{capi.ReadyCondition}, Compute: trueWhenAllTrue},
+			},
+			errorMatcher:    IsConditionGraphCycle,
+			expectedMessage: "Ready -> Ready",
+		},
+		{
+			name: "case 4: Duplicate condition is invalid",
+			nodes: []ConditionNode{
+				{Type: capi.ReadyCondition, Compute: trueWhenAllTrue},
+				{Type: capi.ReadyCondition, Compute: trueWhenAllTrue},
+			},
+			errorMatcher: IsInvalidConditionGraph,
+		},
+		{
+			name: "case 5: Condition without compute function is invalid",
+			nodes: []ConditionNode{
+				{Type: capi.ReadyCondition},
+			},
+			errorMatcher: IsInvalidConditionGraph,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			graph, err := NewConditionGraph(tc.nodes...)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if err != nil {
+				if !strings.Contains(microerror.Pretty(err, false), tc.expectedMessage) {
+					t.Fatalf("expected error message to contain %q, got %q", tc.expectedMessage, microerror.Pretty(err, false))
+				}
+				return
+			}
+
+			order := graph.Order()
+			if fmt.Sprint(order) != fmt.Sprint(tc.expectedOrder) {
+				t.Fatalf("expected order %v, got %v", tc.expectedOrder, order)
+			}
+		})
+	}
+}
+
+func TestConditionGraphEvaluate(t *testing.T) {
+	clock := NewFakeClock(time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC))
+	var computed []capi.ConditionType
+
+	graph, err := NewConditionGraph(
+		NewReadySummaryNode(WithSummaryConditions(InfrastructureReady, NodePoolsReady)),
+		ConditionNode{
+			Type:   NodePoolsReady,
+			Inputs: []capi.ConditionType{capiexp.ReplicasReadyCondition},
+			Compute: func(object Object, inputs map[capi.ConditionType]*capi.Condition) capi.Condition {
+				computed = append(computed, NodePoolsReady)
+				return trueWhenAllTrue(object, inputs)
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	cluster := clusterWithoutConditions()
+	cluster.Status.Conditions = capi.Conditions{
+		{Type: InfrastructureReady, Status: corev1.ConditionTrue},
+		{Type: capiexp.ReplicasReadyCondition, Status: corev1.ConditionFalse, Reason: capiexp.WaitingForReplicasReadyReason},
+	}
+
+	result := graph.Evaluate(cluster, WithClock(clock))
+
+	if len(result) != 2 || result[0].Type != NodePoolsReady || result[1].Type != capi.ReadyCondition {
+		t.Fatalf("expected NodePoolsReady and Ready to be computed, got %v", result)
+	}
+	if !IsReadyFalse(cluster, WithReason("ReplicasReadyNotTrue")) {
+		t.Fatalf("expected Ready to be computed from derived NodePoolsReady, got %s", sprintConditionForObject(cluster, capi.ReadyCondition))
+	}
+
+	// Derived conditions are recomputed in one pass when an input changes.
+	clock.Step(time.Minute)
+	capiconditions.MarkTrue(cluster, capiexp.ReplicasReadyCondition)
+	graph.Evaluate(cluster, WithClock(clock))
+
+	if !IsNodePoolsReadyTrue(cluster) || !IsReadyTrue(cluster) {
+		t.Fatalf("expected NodePoolsReady and Ready to be True, got %s and %s", sprintConditionForObject(cluster, NodePoolsReady), sprintConditionForObject(cluster, capi.ReadyCondition))
+	}
+	if len(computed) != 2 {
+		t.Fatalf("expected NodePoolsReady to be computed once per evaluation, got %d", len(computed))
+	}
+	if lastTransitionTime := capiconditions.GetLastTransitionTime(cluster, capi.ReadyCondition); !lastTransitionTime.Time.Equal(clock.Now()) {
+		t.Fatalf("expected Ready LastTransitionTime %s, got %s", clock.Now(), lastTransitionTime)
+	}
+}
@@ -22,6 +22,26 @@ func clusterWith(conditionType capi.ConditionType, conditionStatus corev1.Condit
 	}
 }
 
+func clusterWithCreatingFalseAnd(upgradingStatus corev1.ConditionStatus, upgradingReason string) *capi.Cluster {
+	return &capi.Cluster{
+		Status: capi.ClusterStatus{
+			Conditions: capi.Conditions{
+				{
+					Type:     Creating,
+					Status:   corev1.ConditionFalse,
+					Reason:   CreationCompletedReason,
+					Severity: capi.ConditionSeverityInfo,
+				},
+				{
+					Type:   Upgrading,
+					Status: upgradingStatus,
+					Reason: upgradingReason,
+				},
+			},
+		},
+	}
+}
+
 func clusterWithoutConditions() *capi.Cluster {
 	return &capi.Cluster{
 		Status: capi.ClusterStatus{
@@ -0,0 +1,353 @@
+package conditions
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// HistoryAnnotation is the annotation in which condition transition
+	// history is stored as a JSON array of HistoryEntry objects, ordered from
+	// the oldest to the newest entry.
+	HistoryAnnotation = "conditions.giantswarm.io/history"
+
+	// DefaultHistoryLimit is the default maximum number of entries that are
+	// kept in the history.
+	DefaultHistoryLimit = 50
+
+	// DefaultHistoryMaxSize is the default maximum size of the history
+	// annotation value in bytes. Oldest entries are dropped until the
+	// history fits.
+	DefaultHistoryMaxSize = 8 * 1024
+)
+
+// HistoryEntry is a single condition transition, i.e. a change of condition
+// status or reason. JSON field names are kept short, because the history is
+// stored in an annotation.
+type HistoryEntry struct {
+	Type     capi.ConditionType     `json:"t"`
+	Status   corev1.ConditionStatus `json:"s"`
+	Reason   string                 `json:"r,omitempty"`
+	Severity capi.ConditionSeverity `json:"v,omitempty"`
+	Time     metav1.Time            `json:"at"`
+}
+
+// History contains condition transitions ordered from the oldest to the
+// newest one.
+type History []HistoryEntry
+
+// ForType returns only transitions of the condition with the specified type.
+func (h History) ForType(conditionType capi.ConditionType) History {
+	return h.Filter(func(entry HistoryEntry) bool {
+		return entry.Type == conditionType
+	})
+}
+
+// Since returns only transitions that happened at or after the specified
+// time.
+func (h History) Since(t time.Time) History {
+	return h.Filter(func(entry HistoryEntry) bool {
+		return !entry.Time.Time.Before(t)
+	})
+}
+
+// Filter returns only transitions for which the specified function returns
+// true.
+func (h History) Filter(f func(entry HistoryEntry) bool) History {
+	var result History
+	for _, entry := range h {
+		if f(entry) {
+			result = append(result, entry)
+		}
+	}
+
+	return result
+}
+
+// Last returns the newest transition of the condition with the specified
+// type and true. If there is no such transition, it returns an empty struct
+// and false.
+func (h History) Last(conditionType capi.ConditionType) (HistoryEntry, bool) {
+	for i := len(h) - 1; i >= 0; i-- {
+		if h[i].Type == conditionType {
+			return h[i], true
+		}
+	}
+
+	return HistoryEntry{}, false
+}
+
+// TimeIn returns the total time that the condition with the specified type
+// has spent with the specified status and reason, as far as it is known from
+// the history. Empty reason matches any reason. When the condition is still
+// in the specified state, the time until now is counted as well.
+//
+// Example:
+//
+//    history, err := conditions.GetHistory(cluster)
+//    pending := history.TimeIn(conditions.Upgrading, corev1.ConditionFalse, conditions.UpgradePendingReason, time.Now())
+//
+func (h History) TimeIn(conditionType capi.ConditionType, status corev1.ConditionStatus, reason string, now time.Time) time.Duration {
+	var total time.Duration
+	var since *time.Time
+	for _, entry := range h.ForType(conditionType) {
+		entryTime := entry.Time.Time
+		if since != nil {
+			total += entryTime.Sub(*since)
+			since = nil
+		}
+
+		if entry.Status == status && (reason == "" || entry.Reason == reason) {
+			since = &entryTime
+		}
+	}
+
+	if since != nil && now.After(*since) {
+		total += now.Sub(*since)
+	}
+
+	return total
+}
+
+// Transitions returns the number of transitions of the condition with the
+// specified type to the specified status.
+func (h History) Transitions(conditionType capi.ConditionType, status corev1.ConditionStatus) int {
+	return len(h.Filter(func(entry HistoryEntry) bool {
+		return entry.Type == conditionType && entry.Status == status
+	}))
+}
+
+// MergeHistory merges multiple histories into one, ordered by time, where
+// duplicated entries are kept only once. It can be used to combine history
+// written by concurrent writers.
+func MergeHistory(histories ...History) History {
+	seen := map[HistoryEntry]bool{}
+	var result History
+	for _, history := range histories {
+		for _, entry := range history {
+			// Time is stored in the annotation with precision of seconds.
+			key := entry
+			key.Time = metav1.NewTime(entry.Time.UTC().Truncate(time.Second))
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			result = append(result, entry)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time.Before(&result[j].Time)
+	})
+
+	return result
+}
+
+// GetHistory returns the condition transition history stored in the
+// annotation of the specified object. It returns empty history when the
+// annotation is not set and InvalidHistoryError when the annotation cannot
+// be parsed.
+func GetHistory(object metav1.Object) (History, error) {
+	value, ok := object.GetAnnotations()[HistoryAnnotation]
+	if !ok || value == "" {
+		return nil, nil
+	}
+
+	var history History
+	err := json.Unmarshal([]byte(value), &history)
+	if err != nil {
+		return nil, microerror.Maskf(InvalidHistoryError, "%s", InvalidHistoryErrorMessage(object, err))
+	}
+
+	return history, nil
+}
+
+type historyOptions struct {
+	timeOptions
+
+	limit   int
+	maxSize int
+}
+
+// HistoryOption is an option for history functions and
+// HistoryRecordingObject. TimeOption can be used as HistoryOption too, e.g.
+// WithClock sets the clock used for transitions without LastTransitionTime.
+type HistoryOption interface {
+	applyToHistory(options *historyOptions)
+}
+
+type historyOptionFunc func(options *historyOptions)
+
+func (f historyOptionFunc) applyToHistory(options *historyOptions) {
+	f(options)
+}
+
+func (o TimeOption) applyToHistory(options *historyOptions) {
+	o(&options.timeOptions)
+}
+
+// WithHistoryLimit returns a HistoryOption that sets the maximum number of
+// entries kept in the history. Default is DefaultHistoryLimit.
+func WithHistoryLimit(limit int) HistoryOption {
+	return historyOptionFunc(func(options *historyOptions) {
+		options.limit = limit
+	})
+}
+
+// WithHistoryMaxSize returns a HistoryOption that sets the maximum size of
+// the history annotation value in bytes. Default is DefaultHistoryMaxSize.
+func WithHistoryMaxSize(maxSize int) HistoryOption {
+	return historyOptionFunc(func(options *historyOptions) {
+		options.maxSize = maxSize
+	})
+}
+
+func newHistoryOptions(options ...HistoryOption) historyOptions {
+	historyOpts := historyOptions{
+		limit:   DefaultHistoryLimit,
+		maxSize: DefaultHistoryMaxSize,
+	}
+	for _, option := range options {
+		option.applyToHistory(&historyOpts)
+	}
+
+	return historyOpts
+}
+
+// AppendHistory appends specified entries to the history stored in the
+// annotation of the specified object. Entries are merged with the history
+// that is currently set in the annotation, so a writer that re-reads the
+// object after an update conflict and appends the same entries again does
+// not lose entries written by other writers, nor duplicate its own. When
+// the history exceeds the limit or the maximum annotation size, the oldest
+// entries are dropped. When the annotation cannot be parsed, it returns
+// InvalidHistoryError and the annotation is not changed.
+func AppendHistory(object metav1.Object, entries []HistoryEntry, options ...HistoryOption) error {
+	current, err := GetHistory(object)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	setHistory(object, MergeHistory(current, entries), newHistoryOptions(options...))
+	return nil
+}
+
+// PruneHistory removes all history entries older than the specified time
+// from the annotation of the specified object. The annotation is removed
+// when no entries are left.
+func PruneHistory(object metav1.Object, olderThan time.Time, options ...HistoryOption) error {
+	history, err := GetHistory(object)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	setHistory(object, history.Since(olderThan), newHistoryOptions(options...))
+	return nil
+}
+
+func setHistory(object metav1.Object, history History, historyOpts historyOptions) {
+	if historyOpts.limit > 0 && len(history) > historyOpts.limit {
+		history = history[len(history)-historyOpts.limit:]
+	}
+
+	annotations := object.GetAnnotations()
+	for len(history) > 0 {
+		value, err := json.Marshal(history)
+		if err != nil {
+			// History contains only strings and times, so this cannot
+			// happen, but history must never break setting conditions.
+			break
+		}
+
+		if historyOpts.maxSize <= 0 || len(value) <= historyOpts.maxSize {
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[HistoryAnnotation] = string(value)
+			object.SetAnnotations(annotations)
+			return
+		}
+
+		// Drop the oldest entry, like in a ring buffer, until the history
+		// fits into the annotation.
+		history = history[1:]
+	}
+
+	delete(annotations, HistoryAnnotation)
+	object.SetAnnotations(annotations)
+}
+
+// HistoryRecordingObject wraps an Object and records every change of
+// condition status or reason made with SetConditions, i.e. with any function
+// from this package or from Cluster API conditions package that sets
+// conditions, into the history annotation on the wrapped object (see
+// AppendHistory). Since SetConditions cannot return an error, the error that
+// occurred while recording the history, e.g. when the annotation cannot be
+// parsed, is returned by Err.
+//
+// Example:
+//
+//    object := conditions.NewHistoryRecordingObject(cluster, conditions.WithHistoryLimit(20))
+//    err := conditions.MarkUpgradePending(object)
+//    // ...
+//    err = object.Err()
+//    // update cluster ...
+//
+type HistoryRecordingObject struct {
+	transitionObservingObject
+
+	options []HistoryOption
+	err     error
+}
+
+// NewHistoryRecordingObject returns a new HistoryRecordingObject that wraps
+// the specified object.
+func NewHistoryRecordingObject(object Object, options ...HistoryOption) *HistoryRecordingObject {
+	o := &HistoryRecordingObject{
+		options: options,
+	}
+	o.transitionObservingObject = newTransitionObservingObject(object, o.recordHistory)
+
+	return o
+}
+
+// Err returns the last error that occurred while recording the history, or
+// nil. Conditions are set on the wrapped object even when the history
+// cannot be recorded.
+func (o *HistoryRecordingObject) Err() error {
+	return o.err
+}
+
+func (o *HistoryRecordingObject) recordHistory(transitions ConditionChanges) {
+	clock := newHistoryOptions(o.options...).clockOrDefault()
+	var entries []HistoryEntry
+	for _, change := range transitions {
+		entries = append(entries, historyEntry(change.After, clock))
+	}
+
+	err := AppendHistory(o.Object, entries, o.options...)
+	if err != nil {
+		o.err = microerror.Mask(err)
+	}
+}
+
+func historyEntry(condition *capi.Condition, clock Clock) HistoryEntry {
+	transitionTime := condition.LastTransitionTime
+	if transitionTime.IsZero() {
+		transitionTime = now(clock)
+	}
+
+	return HistoryEntry{
+		Type:     condition.Type,
+		Status:   condition.Status,
+		Reason:   condition.Reason,
+		Severity: condition.Severity,
+		Time:     transitionTime,
+	}
+}
@@ -0,0 +1,346 @@
+package conditions
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func historyEntryAt(minute int, conditionType capi.ConditionType, status corev1.ConditionStatus, reason string) HistoryEntry {
+	return HistoryEntry{
+		Type:   conditionType,
+		Status: status,
+		Reason: reason,
+		Time:   metav1.NewTime(time.Date(2021, 1, 1, 12, minute, 0, 0, time.UTC)),
+	}
+}
+
+func TestHistoryRecordingObject(t *testing.T) {
+	testCases := []struct {
+		name            string
+		object          Object
+		options         []HistoryOption
+		update          func(object Object)
+		expectedHistory []string
+	}{
+		{
+			name:   "case 0: Added condition is recorded",
+			object: clusterWithoutConditions(),
+			update: func(object Object) {
+				_ = MarkCreatingTrue(object)
+			},
+			expectedHistory: []string{"Creating True"},
+		},
+		{
+			name:   "case 1: Status and reason changes are recorded",
+			object: clusterWithCreatingFalseAnd(corev1.ConditionFalse, UpgradeNotStartedReason),
+			update: func(object Object) {
+				_ = MarkUpgradePending(object)
+				_ = MarkUpgradingTrue(object)
+				_ = MarkUpgradeCompleted(object)
+			},
+			expectedHistory: []string{
+				"Upgrading False UpgradePending",
+				"Upgrading True",
+				"Upgrading False UpgradeCompleted",
+			},
+		},
+		{
+			name:   "case 2: Message and severity changes are not recorded",
+			object: clusterWithoutConditions(),
+			update: func(object Object) {
+				capiconditions.MarkFalse(object, capi.ReadyCondition, "NotReady", capi.ConditionSeverityInfo, "a")
+				capiconditions.MarkFalse(object, capi.ReadyCondition, "NotReady", capi.ConditionSeverityWarning, "b")
+			},
+			expectedHistory: []string{"Ready False NotReady"},
+		},
+		{
+			name:    "case 3: Oldest entries are dropped when limit is reached",
+			object:  clusterWithoutConditions(),
+			options: []HistoryOption{WithHistoryLimit(2)},
+			update: func(object Object) {
+				capiconditions.MarkFalse(object, capi.ReadyCondition, "A", capi.ConditionSeverityInfo, "")
+				capiconditions.MarkTrue(object, capi.ReadyCondition)
+				capiconditions.MarkFalse(object, capi.ReadyCondition, "B", capi.ConditionSeverityInfo, "")
+			},
+			expectedHistory: []string{"Ready True", "Ready False B"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+			object := NewHistoryRecordingObject(tc.object, tc.options...)
+
+			tc.update(object)
+
+			history, err := GetHistory(tc.object)
+			if err != nil {
+				t.Fatalf("expected no error, got %#v", err)
+			}
+
+			var got []string
+			for _, entry := range history {
+				text := string(entry.Type) + " " + string(entry.Status)
+				if entry.Reason != "" {
+					text += " " + entry.Reason
+				}
+				got = append(got, text)
+			}
+
+			if len(got) != len(tc.expectedHistory) {
+				t.Fatalf("expected history %q, got %q", tc.expectedHistory, got)
+			}
+			for i := range got {
+				if got[i] != tc.expectedHistory[i] {
+					t.Fatalf("expected history %q, got %q", tc.expectedHistory, got)
+				}
+			}
+		})
+	}
+}
+
+func TestHistoryRecordingObjectConcurrentWriters(t *testing.T) {
+	stored := clusterWithoutConditions()
+
+	// Two controllers read the same version of the object.
+	cluster1 := stored.DeepCopy()
+	cluster2 := stored.DeepCopy()
+
+	capiconditions.MarkFalse(NewHistoryRecordingObject(cluster1), capi.ReadyCondition, "NotReady", capi.ConditionSeverityInfo, "")
+	stored = cluster1.DeepCopy()
+
+	// Update of the second controller fails with a conflict, so it
+	// re-reads the object and applies its change again.
+	capiconditions.MarkTrue(NewHistoryRecordingObject(cluster2), NodePoolsReady)
+	cluster2 = stored.DeepCopy()
+	capiconditions.MarkTrue(NewHistoryRecordingObject(cluster2), NodePoolsReady)
+
+	history, err := GetHistory(cluster2)
+	if err != nil {
+		t.Fatalf("expected no error, got %#v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 entries, got %v", history)
+	}
+	if _, ok := history.Last(capi.ReadyCondition); !ok {
+		t.Fatalf("expected transition written by the other controller, got %v", history)
+	}
+}
+
+func TestHistoryRecordingObjectErrorAndClock(t *testing.T) {
+	clock := NewFakeClock(time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC))
+	cluster := clusterWithoutConditions()
+	object := NewHistoryRecordingObject(cluster, WithClock(clock))
+
+	// Condition without LastTransitionTime is recorded with time from the
+	// clock.
+	object.SetConditions(capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionTrue}})
+	history, err := GetHistory(cluster)
+	if err != nil {
+		t.Fatalf("expected no error, got %#v", err)
+	}
+	if len(history) != 1 || !history[0].Time.Time.Equal(clock.Now()) {
+		t.Fatalf("expected entry recorded at %s, got %v", clock.Now(), history)
+	}
+	if object.Err() != nil {
+		t.Fatalf("expected no error, got %#v", object.Err())
+	}
+
+	// Invalid annotation is not replaced and the error is returned by Err,
+	// while conditions are still set.
+	cluster.SetAnnotations(map[string]string{HistoryAnnotation: "{"})
+	capiconditions.MarkFalse(object, capi.ReadyCondition, "NotReady", capi.ConditionSeverityInfo, "")
+	if !IsInvalidHistory(object.Err()) {
+		t.Fatalf("expected invalid history error, got %#v", object.Err())
+	}
+	if cluster.GetAnnotations()[HistoryAnnotation] != "{" {
+		t.Fatalf("expected invalid annotation to be kept, got %q", cluster.GetAnnotations()[HistoryAnnotation])
+	}
+	if !IsReadyFalse(cluster) {
+		t.Fatalf("expected Ready to be set, got %s", sprintConditionForObject(cluster, capi.ReadyCondition))
+	}
+}
+
+func TestAppendHistory(t *testing.T) {
+	testCases := []struct {
+		name               string
+		annotation         string
+		entries            []HistoryEntry
+		options            []HistoryOption
+		errorMatcher       func(error) bool
+		expectedEntries    int
+		expectedAnnotation bool
+	}{
+		{
+			name:               "case 0: Entries are appended to empty history",
+			entries:            []HistoryEntry{historyEntryAt(0, capi.ReadyCondition, corev1.ConditionTrue, "")},
+			expectedEntries:    1,
+			expectedAnnotation: true,
+		},
+		{
+			name:       "case 1: Entries written by another writer are kept and duplicates are dropped",
+			annotation: `[{"t":"Ready","s":"True","at":"2021-01-01T12:00:00Z"},{"t":"Creating","s":"True","at":"2021-01-01T12:01:00Z"}]`,
+			entries: []HistoryEntry{
+				historyEntryAt(0, capi.ReadyCondition, corev1.ConditionTrue, ""),
+				historyEntryAt(2, capi.ReadyCondition, corev1.ConditionFalse, "NotReady"),
+			},
+			expectedEntries:    3,
+			expectedAnnotation: true,
+		},
+		{
+			name:               "case 2: Error is returned and invalid annotation is kept",
+			annotation:         `{not json`,
+			entries:            []HistoryEntry{historyEntryAt(0, capi.ReadyCondition, corev1.ConditionTrue, "")},
+			errorMatcher:       IsInvalidHistory,
+			expectedAnnotation: true,
+		},
+		{
+			name: "case 3: Oldest entries are dropped when annotation is too big",
+			entries: []HistoryEntry{
+				historyEntryAt(0, capi.ReadyCondition, corev1.ConditionTrue, ""),
+				historyEntryAt(1, capi.ReadyCondition, corev1.ConditionFalse, "NotReady"),
+				historyEntryAt(2, capi.ReadyCondition, corev1.ConditionTrue, ""),
+			},
+			options:            []HistoryOption{WithHistoryMaxSize(130)},
+			expectedEntries:    2,
+			expectedAnnotation: true,
+		},
+		{
+			name:               "case 4: Annotation is removed when no entry fits",
+			annotation:         `[{"t":"Ready","s":"True","at":"2021-01-01T12:00:00Z"}]`,
+			entries:            []HistoryEntry{historyEntryAt(1, capi.ReadyCondition, corev1.ConditionFalse, "NotReady")},
+			options:            []HistoryOption{WithHistoryMaxSize(10)},
+			expectedEntries:    0,
+			expectedAnnotation: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+			cluster := clusterWithoutConditions()
+			if tc.annotation != "" {
+				cluster.SetAnnotations(map[string]string{HistoryAnnotation: tc.annotation})
+			}
+
+			err := AppendHistory(cluster, tc.entries, tc.options...)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			annotation, ok := cluster.GetAnnotations()[HistoryAnnotation]
+			if ok != tc.expectedAnnotation {
+				t.Fatalf("expected annotation set %t, got %t", tc.expectedAnnotation, ok)
+			}
+			if err != nil {
+				if annotation != tc.annotation {
+					t.Fatalf("expected annotation %q not to be changed, got %q", tc.annotation, annotation)
+				}
+				return
+			}
+
+			history, err := GetHistory(cluster)
+			if err != nil {
+				t.Fatalf("expected no error, got %#v", err)
+			}
+			if len(history) != tc.expectedEntries {
+				t.Fatalf("expected %d entries, got %d: %v", tc.expectedEntries, len(history), history)
+			}
+			for i := 1; i < len(history); i++ {
+				if history[i].Time.Before(&history[i-1].Time) {
+					t.Fatalf("expected history ordered by time, got %v", history)
+				}
+			}
+		})
+	}
+}
+
+func TestGetHistoryInvalidAnnotation(t *testing.T) {
+	cluster := clusterWithoutConditions()
+	cluster.SetAnnotations(map[string]string{HistoryAnnotation: "{"})
+
+	_, err := GetHistory(cluster)
+	if !IsInvalidHistory(err) {
+		t.Fatalf("expected invalid history error, got %#v", err)
+	}
+
+	err = PruneHistory(cluster, time.Now())
+	if !IsInvalidHistory(err) {
+		t.Fatalf("expected invalid history error, got %#v", err)
+	}
+}
+
+func TestPruneHistory(t *testing.T) {
+	cluster := clusterWithoutConditions()
+	err := AppendHistory(cluster, []HistoryEntry{
+		historyEntryAt(0, capi.ReadyCondition, corev1.ConditionFalse, "NotReady"),
+		historyEntryAt(10, capi.ReadyCondition, corev1.ConditionTrue, ""),
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %#v", err)
+	}
+
+	err = PruneHistory(cluster, time.Date(2021, 1, 1, 12, 5, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("expected no error, got %#v", err)
+	}
+	history, _ := GetHistory(cluster)
+	if len(history) != 1 || history[0].Status != corev1.ConditionTrue {
+		t.Fatalf("expected only newest entry, got %v", history)
+	}
+
+	err = PruneHistory(cluster, time.Date(2021, 1, 1, 13, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("expected no error, got %#v", err)
+	}
+	if _, ok := cluster.GetAnnotations()[HistoryAnnotation]; ok {
+		t.Fatalf("expected annotation to be removed")
+	}
+}
+
+func TestHistoryQueries(t *testing.T) {
+	history := History{
+		historyEntryAt(0, Upgrading, corev1.ConditionFalse, UpgradePendingReason),
+		historyEntryAt(5, Upgrading, corev1.ConditionTrue, ""),
+		historyEntryAt(6, capi.ReadyCondition, corev1.ConditionFalse, "NotReady"),
+		historyEntryAt(20, Upgrading, corev1.ConditionFalse, UpgradeCompletedReason),
+		historyEntryAt(30, Upgrading, corev1.ConditionFalse, UpgradePendingReason),
+	}
+	now := time.Date(2021, 1, 1, 12, 40, 0, 0, time.UTC)
+
+	pending := history.TimeIn(Upgrading, corev1.ConditionFalse, UpgradePendingReason, now)
+	if pending != 15*time.Minute {
+		t.Fatalf("expected 15m in UpgradePending, got %s", pending)
+	}
+
+	upgrading := history.TimeIn(Upgrading, corev1.ConditionTrue, "", now)
+	if upgrading != 15*time.Minute {
+		t.Fatalf("expected 15m in Upgrading, got %s", upgrading)
+	}
+
+	if n := history.Transitions(Upgrading, corev1.ConditionFalse); n != 3 {
+		t.Fatalf("expected 3 transitions, got %d", n)
+	}
+
+	last, ok := history.Last(capi.ReadyCondition)
+	if !ok || last.Reason != "NotReady" {
+		t.Fatalf("expected last Ready transition, got %v", last)
+	}
+
+	if n := len(history.Since(time.Date(2021, 1, 1, 12, 6, 0, 0, time.UTC))); n != 3 {
+		t.Fatalf("expected 3 entries since 12:06, got %d", n)
+	}
+}
@@ -3,6 +3,7 @@ package conditions
 import (
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
 	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
 )
@@ -51,49 +52,85 @@ const (
 	WaitingForInfrastructureWarningThresholdTime = 10 * time.Minute
 )
 
+// infrastructureReadyDescriptor describes InfrastructureReady condition.
+var infrastructureReadyDescriptor = ConditionDescriptor{
+	Type: InfrastructureReady,
+	Reasons: map[string]capi.ConditionSeverity{
+		InfrastructureReferenceNotSetReason:         capi.ConditionSeverityWarning,
+		InfrastructureObjectNotFoundReason:          capi.ConditionSeverityWarning,
+		capi.WaitingForInfrastructureFallbackReason: capi.ConditionSeverityInfo,
+	},
+	TargetKinds: []string{"Cluster", "Machine", "MachinePool"},
+}
+
+// InfrastructureReadyDescriptor returns a copy of the descriptor of
+// InfrastructureReady condition.
+func InfrastructureReadyDescriptor() ConditionDescriptor {
+	return infrastructureReadyDescriptor.deepCopy()
+}
+
 // GetInfrastructureReady tries to get InfrastructureReady condition from the
-// specified Cluster CR. If the InfrastructureReady condition was found, it
+// specified object. If the InfrastructureReady condition was found, it
 // returns a copy of the condition and true, otherwise it returns an empty
 // struct and false.
-func GetInfrastructureReady(cluster *capi.Cluster) (capi.Condition, bool) {
-	infrastructureReady := capiconditions.Get(cluster, InfrastructureReady)
-
-	if infrastructureReady != nil {
-		return *infrastructureReady, true
-	} else {
-		return capi.Condition{}, false
-	}
+func GetInfrastructureReady(object Object) (capi.Condition, bool) {
+	return infrastructureReadyDescriptor.Get(object)
 }
 
 // IsInfrastructureReadyTrue checks if specified object is in InfrastructureReady
 // condition (if InfrastructureReady condition is set with status True).
 func IsInfrastructureReadyTrue(object Object) bool {
-	return capiconditions.IsTrue(object, InfrastructureReady)
+	return infrastructureReadyDescriptor.IsTrue(object)
 }
 
 // IsInfrastructureReadyFalse checks if specified object is not in
 // InfrastructureReady condition (if InfrastructureReady condition is set with
 // status False) and if optionally specified checks are successful.
 func IsInfrastructureReadyFalse(object Object, checkOptions ...CheckOption) bool {
-	condition := capiconditions.Get(object, InfrastructureReady)
-	if !IsFalse(condition) {
-		// Condition is not set or it does not have status False
-		return false
-	}
-
-	for _, checkOption := range checkOptions {
-		if !checkOption(condition) {
-			// additional check has failed
-			return false
-		}
-	}
-
-	return true
+	return infrastructureReadyDescriptor.IsFalse(object, checkOptions...)
 }
 
 // IsInfrastructureReadyUnknown checks if it is unknown whether the specified
 // object is in InfrastructureReady condition or not (if InfrastructureReady
 // condition is not set, or it is set with status Unknown).
 func IsInfrastructureReadyUnknown(object Object) bool {
-	return capiconditions.IsUnknown(object, InfrastructureReady)
+	return infrastructureReadyDescriptor.IsUnknown(object)
+}
+
+var infrastructureReadyMirrorSpec = mirrorSpec{
+	descriptor:            &infrastructureReadyDescriptor,
+	description:           "Infrastructure",
+	referenceNotSetReason: InfrastructureReferenceNotSetReason,
+	objectNotFoundReason:  InfrastructureObjectNotFoundReason,
+	waitingReason:         capi.WaitingForInfrastructureFallbackReason,
+	warningThresholdTime:  WaitingForInfrastructureWarningThresholdTime,
+}
+
+// UpdateInfrastructureReady sets InfrastructureReady condition on the specified
+// object by mirroring Ready condition from the referenced infrastructure
+// object, e.g. from AzureCluster for Cluster CR. The condition is set in the
+// following way:
+//
+//   - When the reference is not set, it is set with status False, reason
+//     InfrastructureReferenceNotSet and severity Warning.
+//   - When the referenced object is nil (i.e. it is not found), it is set with
+//     status False, reason InfrastructureObjectNotFound and severity Warning.
+//   - When the referenced object does not have Ready condition, it is set
+//     with status False, reason WaitingForInfrastructure and severity Info.
+//   - Otherwise, Ready condition of the referenced object is mirrored.
+//
+// Severity Info is escalated to Warning after
+// WaitingForInfrastructureWarningThresholdTime (see EscalateSeverity). It
+// returns the duration after which the next severity escalation is due, or 0.
+// Current time is taken from the clock set with WithClock.
+//
+// Example:
+//
+//    requeueAfter := conditions.UpdateInfrastructureReady(
+//        cluster,
+//        cluster.Spec.InfrastructureRef,
+//        azureCluster) // nil when AzureCluster is not found
+//
+func UpdateInfrastructureReady(object Object, infrastructureRef *corev1.ObjectReference, infrastructureObject capiconditions.Getter, options ...TimeOption) time.Duration {
+	return updateMirror(object, infrastructureReadyMirrorSpec, infrastructureRef, infrastructureObject, clockOf(options))
 }
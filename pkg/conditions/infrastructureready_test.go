@@ -7,6 +7,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
 )
 
 func TestGetInfrastructureReady(t *testing.T) {
@@ -220,3 +221,133 @@ func Test_IsInfrastructureReadyUnknown(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateInfrastructureReady(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	reference := &corev1.ObjectReference{Kind: "AzureCluster", Namespace: "org-test", Name: "test"}
+
+	testCases := []struct {
+		name                 string
+		existingCondition    *capi.Condition
+		reference            *corev1.ObjectReference
+		infrastructureObject capiconditions.Getter
+		expectedStatus       corev1.ConditionStatus
+		expectedReason       string
+		expectedSeverity     capi.ConditionSeverity
+		expectedRequeueAfter time.Duration
+	}{
+		{
+			name:             "case 0: InfrastructureReady is False with reason InfrastructureReferenceNotSet when reference is nil",
+			reference:        nil,
+			expectedStatus:   corev1.ConditionFalse,
+			expectedReason:   InfrastructureReferenceNotSetReason,
+			expectedSeverity: capi.ConditionSeverityWarning,
+		},
+		{
+			name:                 "case 1: InfrastructureReady is False with reason InfrastructureObjectNotFound when object is nil",
+			reference:            reference,
+			infrastructureObject: (*capi.Cluster)(nil),
+			expectedStatus:       corev1.ConditionFalse,
+			expectedReason:       InfrastructureObjectNotFoundReason,
+			expectedSeverity:     capi.ConditionSeverityWarning,
+		},
+		{
+			name:                 "case 2: InfrastructureReady is False with reason WaitingForInfrastructure when object does not have Ready condition",
+			reference:            reference,
+			infrastructureObject: clusterWithoutConditions(),
+			expectedStatus:       corev1.ConditionFalse,
+			expectedReason:       capi.WaitingForInfrastructureFallbackReason,
+			expectedSeverity:     capi.ConditionSeverityInfo,
+			expectedRequeueAfter: WaitingForInfrastructureWarningThresholdTime,
+		},
+		{
+			name:                 "case 3: InfrastructureReady mirrors Ready condition with status True",
+			reference:            reference,
+			infrastructureObject: clusterWith(capi.ReadyCondition, corev1.ConditionTrue),
+			expectedStatus:       corev1.ConditionTrue,
+		},
+		{
+			name: "case 4: InfrastructureReady mirrors Ready condition with status False and severity Info is escalated to Warning",
+			existingCondition: &capi.Condition{
+				Type:               InfrastructureReady,
+				Status:             corev1.ConditionFalse,
+				Reason:             "Provisioning",
+				Severity:           capi.ConditionSeverityWarning,
+				LastTransitionTime: metav1.NewTime(now.Add(-15 * time.Minute)),
+			},
+			reference: reference,
+			infrastructureObject: &capi.Cluster{
+				Status: capi.ClusterStatus{
+					Conditions: capi.Conditions{
+						{
+							Type:     capi.ReadyCondition,
+							Status:   corev1.ConditionFalse,
+							Reason:   "Provisioning",
+							Severity: capi.ConditionSeverityInfo,
+						},
+					},
+				},
+			},
+			expectedStatus:   corev1.ConditionFalse,
+			expectedReason:   "Provisioning",
+			expectedSeverity: capi.ConditionSeverityWarning,
+		},
+		{
+			name: "case 5: InfrastructureReady mirrors Ready condition with status False and severity Error",
+			existingCondition: &capi.Condition{
+				Type:               InfrastructureReady,
+				Status:             corev1.ConditionFalse,
+				Reason:             "Provisioning",
+				Severity:           capi.ConditionSeverityInfo,
+				LastTransitionTime: metav1.NewTime(now.Add(-5 * time.Minute)),
+			},
+			reference: reference,
+			infrastructureObject: &capi.Cluster{
+				Status: capi.ClusterStatus{
+					Conditions: capi.Conditions{
+						{
+							Type:     capi.ReadyCondition,
+							Status:   corev1.ConditionFalse,
+							Reason:   "ProvisioningFailed",
+							Severity: capi.ConditionSeverityError,
+						},
+					},
+				},
+			},
+			expectedStatus:   corev1.ConditionFalse,
+			expectedReason:   "ProvisioningFailed",
+			expectedSeverity: capi.ConditionSeverityError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+			cluster := clusterWithoutConditions()
+			if tc.existingCondition != nil {
+				cluster.Status.Conditions = capi.Conditions{*tc.existingCondition}
+			}
+
+			requeueAfter := UpdateInfrastructureReady(cluster, tc.reference, tc.infrastructureObject, WithClock(NewFakeClock(now)))
+
+			condition := capiconditions.Get(cluster, InfrastructureReady)
+			if condition == nil ||
+				condition.Status != tc.expectedStatus ||
+				condition.Reason != tc.expectedReason ||
+				condition.Severity != tc.expectedSeverity {
+				t.Logf(
+					"expected InfrastructureReady(Status=%q, Reason=%q, Severity=%q), got %s",
+					tc.expectedStatus,
+					tc.expectedReason,
+					tc.expectedSeverity,
+					sprintCondition(condition))
+				t.Fail()
+			}
+
+			if requeueAfter != tc.expectedRequeueAfter {
+				t.Logf("expected RequeueAfter %s, got %s", tc.expectedRequeueAfter, requeueAfter)
+				t.Fail()
+			}
+		})
+	}
+}
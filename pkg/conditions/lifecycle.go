@@ -0,0 +1,221 @@
+package conditions
+
+import (
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// LifecyclePhase is a phase of the object lifecycle, which is computed from
+// Creating and Upgrading conditions.
+type LifecyclePhase string
+
+const (
+	// LifecyclePhaseNew is a phase of an object that has neither Creating nor
+	// Upgrading condition set, or that has only Upgrading condition set with
+	// reason UpgradeNotStarted, which can be set before the creation starts.
+	LifecyclePhaseNew LifecyclePhase = "New"
+
+	// LifecyclePhaseCreating is a phase of an object that has Creating
+	// condition set with status True. Upgrading condition can be set with
+	// reason UpgradeNotStarted at the same time.
+	LifecyclePhaseCreating LifecyclePhase = "Creating"
+
+	// LifecyclePhaseCreationCompleted is a phase of an object that has
+	// Creating condition set with status False, while Upgrading condition is
+	// not set.
+	LifecyclePhaseCreationCompleted LifecyclePhase = "CreationCompleted"
+
+	// LifecyclePhaseExistingObject is a phase of an object that has Creating
+	// condition set with status False and reason ExistingObject, while
+	// Upgrading condition is not set. It is an entry point to the lifecycle
+	// for objects that were created before conditions were introduced.
+	LifecyclePhaseExistingObject LifecyclePhase = "ExistingObject"
+
+	// LifecyclePhaseUpgradeNotStarted is a phase of a created object that has
+	// Upgrading condition set with status False and reason UpgradeNotStarted.
+	LifecyclePhaseUpgradeNotStarted LifecyclePhase = "UpgradeNotStarted"
+
+	// LifecyclePhaseUpgradePending is a phase of a created object that has
+	// Upgrading condition set with status False and reason UpgradePending.
+	LifecyclePhaseUpgradePending LifecyclePhase = "UpgradePending"
+
+	// LifecyclePhaseUpgrading is a phase of a created object that has
+	// Upgrading condition set with status True.
+	LifecyclePhaseUpgrading LifecyclePhase = "Upgrading"
+
+	// LifecyclePhaseUpgradeCompleted is a phase of a created object that has
+	// Upgrading condition set with status False and reason UpgradeCompleted.
+	LifecyclePhaseUpgradeCompleted LifecyclePhase = "UpgradeCompleted"
+
+	// LifecyclePhaseUnknown is a phase of an object whose Creating and
+	// Upgrading conditions do not match any known phase, e.g. when Upgrading
+	// condition is set while the object is still being created.
+	LifecyclePhaseUnknown LifecyclePhase = "Unknown"
+)
+
+// lifecycleTransitions contains all allowed transitions between different
+// lifecycle phases. Staying in the same phase is always allowed, except for
+// the Unknown phase, so it is not listed here.
+var lifecycleTransitions = map[LifecyclePhase][]LifecyclePhase{
+	LifecyclePhaseNew: {
+		LifecyclePhaseCreating,
+		LifecyclePhaseExistingObject,
+	},
+	LifecyclePhaseCreating: {
+		LifecyclePhaseCreationCompleted,
+		// Upgrading condition with reason UpgradeNotStarted can be set
+		// during creation, so completing the creation moves the object
+		// directly to UpgradeNotStarted phase.
+		LifecyclePhaseUpgradeNotStarted,
+	},
+	LifecyclePhaseCreationCompleted: {
+		LifecyclePhaseUpgradeNotStarted,
+		LifecyclePhaseUpgradePending,
+		LifecyclePhaseUpgrading,
+	},
+	LifecyclePhaseExistingObject: {
+		LifecyclePhaseUpgradeNotStarted,
+		LifecyclePhaseUpgradePending,
+		LifecyclePhaseUpgrading,
+	},
+	LifecyclePhaseUpgradeNotStarted: {
+		LifecyclePhaseUpgradePending,
+		LifecyclePhaseUpgrading,
+	},
+	LifecyclePhaseUpgradePending: {
+		LifecyclePhaseUpgrading,
+	},
+	LifecyclePhaseUpgrading: {
+		LifecyclePhaseUpgradeCompleted,
+	},
+	LifecyclePhaseUpgradeCompleted: {
+		LifecyclePhaseUpgradePending,
+		LifecyclePhaseUpgrading,
+	},
+}
+
+// GetLifecyclePhase returns the current lifecycle phase of the specified
+// object, computed from its Creating and Upgrading conditions.
+func GetLifecyclePhase(object Object) LifecyclePhase {
+	creating := capiconditions.Get(object, Creating)
+	upgrading := capiconditions.Get(object, Upgrading)
+
+	return lifecyclePhaseOf(creating, upgrading)
+}
+
+// AllowedLifecycleTransitions returns lifecycle phases to which an object
+// can transition from the specified phase, not including the specified phase
+// itself.
+func AllowedLifecycleTransitions(from LifecyclePhase) []LifecyclePhase {
+	allowed := lifecycleTransitions[from]
+	result := make([]LifecyclePhase, len(allowed))
+	copy(result, allowed)
+
+	return result
+}
+
+// IsLifecycleTransitionAllowed checks if an object can transition from one
+// lifecycle phase to another. Staying in the same phase is allowed for all
+// phases except Unknown.
+func IsLifecycleTransitionAllowed(from, to LifecyclePhase) bool {
+	if from == LifecyclePhaseUnknown || to == LifecyclePhaseUnknown {
+		return false
+	}
+
+	if from == to {
+		return true
+	}
+
+	for _, allowed := range lifecycleTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setLifecycleCondition sets specified Creating or Upgrading condition on the
+// object, but only if the lifecycle transition caused by the new condition
+// is allowed. Otherwise, it returns InvalidLifecycleTransitionError and the
+// object is not changed. Creating condition with reason ExistingObject can
+// always be set when Creating condition is not set yet, since it is the
+// entry point to the lifecycle for objects that were created before
+// conditions were introduced, which may already have Upgrading condition.
+// LastTransitionTime is taken from the clock set with WithClock.
+func setLifecycleCondition(object Object, condition *capi.Condition, options ...TimeOption) error {
+	if IsUnsupported(object, Creating) {
+		return NewUnsupportedConditionStatusError(object, Creating)
+	}
+
+	if IsUnsupported(object, Upgrading) {
+		return NewUnsupportedConditionStatusError(object, Upgrading)
+	}
+
+	creating := capiconditions.Get(object, Creating)
+	upgrading := capiconditions.Get(object, Upgrading)
+	from := lifecyclePhaseOf(creating, upgrading)
+	existingObject := condition.Type == Creating && condition.Reason == ExistingObjectReason && IsUnknown(creating)
+
+	switch condition.Type {
+	case Creating:
+		creating = condition
+	case Upgrading:
+		upgrading = condition
+	}
+	to := lifecyclePhaseOf(creating, upgrading)
+
+	if !existingObject && !IsLifecycleTransitionAllowed(from, to) {
+		return microerror.Maskf(InvalidLifecycleTransitionError, "%s", InvalidLifecycleTransitionErrorMessage(object, from, to))
+	}
+
+	setCondition(object, *condition, clockOf(options))
+	return nil
+}
+
+func lifecyclePhaseOf(creating, upgrading *capi.Condition) LifecyclePhase {
+	// Unknown status is handled in the same way as if the condition was
+	// not set at all.
+	if IsUnknown(creating) {
+		if IsUnknown(upgrading) || IsFalse(upgrading, WithUpgradeNotStartedReason()) {
+			return LifecyclePhaseNew
+		}
+
+		// Upgrading condition is set, but Creating is not.
+		return LifecyclePhaseUnknown
+	}
+
+	switch creating.Status {
+	case corev1.ConditionTrue:
+		if IsUnknown(upgrading) || IsFalse(upgrading, WithUpgradeNotStartedReason()) {
+			return LifecyclePhaseCreating
+		}
+
+		// Object cannot be upgraded while it is still being created.
+		return LifecyclePhaseUnknown
+	case corev1.ConditionFalse:
+		// Creation has been completed, continue below.
+	default:
+		return LifecyclePhaseUnknown
+	}
+
+	switch {
+	case IsUnknown(upgrading):
+		if creating.Reason == ExistingObjectReason {
+			return LifecyclePhaseExistingObject
+		}
+		return LifecyclePhaseCreationCompleted
+	case IsTrue(upgrading):
+		return LifecyclePhaseUpgrading
+	case IsFalse(upgrading, WithUpgradeNotStartedReason()):
+		return LifecyclePhaseUpgradeNotStarted
+	case IsFalse(upgrading, WithUpgradePendingReason()):
+		return LifecyclePhaseUpgradePending
+	case IsFalse(upgrading, WithUpgradeCompletedReason()):
+		return LifecyclePhaseUpgradeCompleted
+	default:
+		return LifecyclePhaseUnknown
+	}
+}
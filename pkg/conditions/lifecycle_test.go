@@ -0,0 +1,317 @@
+package conditions
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestGetLifecyclePhase(t *testing.T) {
+	testCases := []struct {
+		name          string
+		object        Object
+		expectedPhase LifecyclePhase
+	}{
+		{
+			name:          "case 0: CR without conditions is New",
+			object:        clusterWithoutConditions(),
+			expectedPhase: LifecyclePhaseNew,
+		},
+		{
+			name:          "case 1: CR with condition Creating with status Unknown is New",
+			object:        machinePoolWith(Creating, corev1.ConditionUnknown),
+			expectedPhase: LifecyclePhaseNew,
+		},
+		{
+			name:          "case 2: CR with condition Creating with status True is Creating",
+			object:        clusterWith(Creating, corev1.ConditionTrue),
+			expectedPhase: LifecyclePhaseCreating,
+		},
+		{
+			name: "case 3: CR with condition Creating with status True and not started upgrade is Creating",
+			object: &capi.Cluster{
+				Status: capi.ClusterStatus{
+					Conditions: capi.Conditions{
+						{Type: Creating, Status: corev1.ConditionTrue},
+						{Type: Upgrading, Status: corev1.ConditionFalse, Reason: UpgradeNotStartedReason},
+					},
+				},
+			},
+			expectedPhase: LifecyclePhaseCreating,
+		},
+		{
+			name:          "case 4: CR with condition Creating with status False is CreationCompleted",
+			object:        clusterWith(Creating, corev1.ConditionFalse),
+			expectedPhase: LifecyclePhaseCreationCompleted,
+		},
+		{
+			name: "case 5: CR with condition Creating with reason ExistingObject is ExistingObject",
+			object: &capi.Cluster{
+				Status: capi.ClusterStatus{
+					Conditions: capi.Conditions{
+						{Type: Creating, Status: corev1.ConditionFalse, Reason: ExistingObjectReason},
+					},
+				},
+			},
+			expectedPhase: LifecyclePhaseExistingObject,
+		},
+		{
+			name:          "case 6: Created CR with condition Upgrading with reason UpgradeNotStarted is UpgradeNotStarted",
+			object:        clusterWithCreatingFalseAnd(corev1.ConditionFalse, UpgradeNotStartedReason),
+			expectedPhase: LifecyclePhaseUpgradeNotStarted,
+		},
+		{
+			name:          "case 7: Created CR with condition Upgrading with reason UpgradePending is UpgradePending",
+			object:        clusterWithCreatingFalseAnd(corev1.ConditionFalse, UpgradePendingReason),
+			expectedPhase: LifecyclePhaseUpgradePending,
+		},
+		{
+			name:          "case 8: Created CR with condition Upgrading with status True is Upgrading",
+			object:        clusterWithCreatingFalseAnd(corev1.ConditionTrue, ""),
+			expectedPhase: LifecyclePhaseUpgrading,
+		},
+		{
+			name:          "case 9: Created CR with condition Upgrading with reason UpgradeCompleted is UpgradeCompleted",
+			object:        clusterWithCreatingFalseAnd(corev1.ConditionFalse, UpgradeCompletedReason),
+			expectedPhase: LifecyclePhaseUpgradeCompleted,
+		},
+		{
+			name:          "case 10: Created CR with condition Upgrading with unknown reason is Unknown",
+			object:        clusterWithCreatingFalseAnd(corev1.ConditionFalse, "SomethingElse"),
+			expectedPhase: LifecyclePhaseUnknown,
+		},
+		{
+			name:          "case 11: CR with condition Upgrading, but without condition Creating is Unknown",
+			object:        machinePoolWith(Upgrading, corev1.ConditionTrue),
+			expectedPhase: LifecyclePhaseUnknown,
+		},
+		{
+			name: "case 12: CR that is being created and upgraded at the same time is Unknown",
+			object: &capi.Cluster{
+				Status: capi.ClusterStatus{
+					Conditions: capi.Conditions{
+						{Type: Creating, Status: corev1.ConditionTrue},
+						{Type: Upgrading, Status: corev1.ConditionTrue},
+					},
+				},
+			},
+			expectedPhase: LifecyclePhaseUnknown,
+		},
+		{
+			name:          "case 13: CR with condition Creating with unsupported status is Unknown",
+			object:        clusterWith(Creating, "NotReally"),
+			expectedPhase: LifecyclePhaseUnknown,
+		},
+		{
+			name: "case 14: CR with condition Upgrading with reason UpgradeNotStarted, but without condition Creating is New",
+			object: &capi.Cluster{
+				Status: capi.ClusterStatus{
+					Conditions: capi.Conditions{
+						{Type: Upgrading, Status: corev1.ConditionFalse, Reason: UpgradeNotStartedReason},
+					},
+				},
+			},
+			expectedPhase: LifecyclePhaseNew,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			phase := GetLifecyclePhase(tc.object)
+			if phase != tc.expectedPhase {
+				t.Logf("expected phase %s, got %s", tc.expectedPhase, phase)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestIsLifecycleTransitionAllowed(t *testing.T) {
+	testCases := []struct {
+		name           string
+		from           LifecyclePhase
+		to             LifecyclePhase
+		expectedOutput bool
+	}{
+		{
+			name:           "case 0: New to Creating is allowed",
+			from:           LifecyclePhaseNew,
+			to:             LifecyclePhaseCreating,
+			expectedOutput: true,
+		},
+		{
+			name:           "case 1: Creating to CreationCompleted is allowed",
+			from:           LifecyclePhaseCreating,
+			to:             LifecyclePhaseCreationCompleted,
+			expectedOutput: true,
+		},
+		{
+			name:           "case 2: UpgradePending to Upgrading is allowed",
+			from:           LifecyclePhaseUpgradePending,
+			to:             LifecyclePhaseUpgrading,
+			expectedOutput: true,
+		},
+		{
+			name:           "case 3: Staying in Upgrading is allowed",
+			from:           LifecyclePhaseUpgrading,
+			to:             LifecyclePhaseUpgrading,
+			expectedOutput: true,
+		},
+		{
+			name:           "case 4: UpgradeCompleted to Upgrading is allowed",
+			from:           LifecyclePhaseUpgradeCompleted,
+			to:             LifecyclePhaseUpgrading,
+			expectedOutput: true,
+		},
+		{
+			name:           "case 5: New to CreationCompleted is not allowed",
+			from:           LifecyclePhaseNew,
+			to:             LifecyclePhaseCreationCompleted,
+			expectedOutput: false,
+		},
+		{
+			name:           "case 6: CreationCompleted to Creating is not allowed",
+			from:           LifecyclePhaseCreationCompleted,
+			to:             LifecyclePhaseCreating,
+			expectedOutput: false,
+		},
+		{
+			name:           "case 7: Upgrading to UpgradePending is not allowed",
+			from:           LifecyclePhaseUpgrading,
+			to:             LifecyclePhaseUpgradePending,
+			expectedOutput: false,
+		},
+		{
+			name:           "case 8: Staying in Unknown is not allowed",
+			from:           LifecyclePhaseUnknown,
+			to:             LifecyclePhaseUnknown,
+			expectedOutput: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			result := IsLifecycleTransitionAllowed(tc.from, tc.to)
+			if result != tc.expectedOutput {
+				t.Logf("expected %t, got %t", tc.expectedOutput, result)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestAllowedLifecycleTransitions(t *testing.T) {
+	allowed := AllowedLifecycleTransitions(LifecyclePhaseUpgrading)
+	if len(allowed) != 1 || allowed[0] != LifecyclePhaseUpgradeCompleted {
+		t.Logf("expected [%s], got %v", LifecyclePhaseUpgradeCompleted, allowed)
+		t.Fail()
+	}
+
+	// Changing returned slice must not change the allowed transitions.
+	allowed[0] = LifecyclePhaseNew
+	if !IsLifecycleTransitionAllowed(LifecyclePhaseUpgrading, LifecyclePhaseUpgradeCompleted) {
+		t.Logf("expected that allowed transitions are not changed")
+		t.Fail()
+	}
+
+	if len(AllowedLifecycleTransitions(LifecyclePhaseUnknown)) != 0 {
+		t.Logf("expected no allowed transitions from phase %s", LifecyclePhaseUnknown)
+		t.Fail()
+	}
+}
+
+func TestLifecycleSetters(t *testing.T) {
+	cluster := clusterWithoutConditions()
+	clock := NewFakeClock(time.Date(2022, 4, 1, 12, 0, 0, 0, time.UTC))
+
+	steps := []struct {
+		mark          func(Object, ...TimeOption) error
+		expectedPhase LifecyclePhase
+	}{
+		{mark: MarkCreatingTrue, expectedPhase: LifecyclePhaseCreating},
+		{mark: MarkUpgradeNotStarted, expectedPhase: LifecyclePhaseCreating},
+		{mark: MarkCreationCompleted, expectedPhase: LifecyclePhaseUpgradeNotStarted},
+		{mark: MarkUpgradePending, expectedPhase: LifecyclePhaseUpgradePending},
+		{mark: MarkUpgradingTrue, expectedPhase: LifecyclePhaseUpgrading},
+		{mark: MarkUpgradeCompleted, expectedPhase: LifecyclePhaseUpgradeCompleted},
+		{mark: MarkUpgradingTrue, expectedPhase: LifecyclePhaseUpgrading},
+	}
+
+	for i, step := range steps {
+		clock.Step(time.Minute)
+		err := step.mark(cluster, WithClock(clock))
+		if err != nil {
+			t.Fatalf("step %d: expected no error, got %#v", i, err)
+		}
+
+		phase := GetLifecyclePhase(cluster)
+		if phase != step.expectedPhase {
+			t.Fatalf("step %d: expected phase %s, got %s", i, step.expectedPhase, phase)
+		}
+
+		// Every step changes status or reason of Creating or Upgrading
+		// condition, so the latest transition happened in this step.
+		var latest time.Time
+		for _, condition := range cluster.GetConditions() {
+			if condition.LastTransitionTime.Time.After(latest) {
+				latest = condition.LastTransitionTime.Time
+			}
+		}
+		if !latest.Equal(clock.Now()) {
+			t.Fatalf("step %d: expected LastTransitionTime %s, got %s", i, clock.Now(), latest)
+		}
+	}
+
+	err := MarkCreatingTrue(cluster)
+	if !IsInvalidLifecycleTransition(err) {
+		t.Fatalf("expected invalid lifecycle transition error, got %#v", err)
+	}
+}
+
+func TestLifecycleSettersAfterUpgradeNotStarted(t *testing.T) {
+	testCases := []struct {
+		name          string
+		mark          func(Object, ...TimeOption) error
+		expectedPhase LifecyclePhase
+	}{
+		{
+			name:          "case 0: Creation is started after upgrade is marked as not started",
+			mark:          MarkCreatingTrue,
+			expectedPhase: LifecyclePhaseCreating,
+		},
+		{
+			name:          "case 1: Object is marked as existing after upgrade is marked as not started",
+			mark:          MarkExistingObject,
+			expectedPhase: LifecyclePhaseUpgradeNotStarted,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+			cluster := clusterWithoutConditions()
+
+			err := MarkUpgradeNotStarted(cluster)
+			if err != nil {
+				t.Fatalf("expected no error, got %#v", err)
+			}
+			if phase := GetLifecyclePhase(cluster); phase != LifecyclePhaseNew {
+				t.Fatalf("expected phase %s, got %s", LifecyclePhaseNew, phase)
+			}
+
+			err = tc.mark(cluster)
+			if err != nil {
+				t.Fatalf("expected no error, got %#v", err)
+			}
+			if phase := GetLifecyclePhase(cluster); phase != tc.expectedPhase {
+				t.Fatalf("expected phase %s, got %s", tc.expectedPhase, phase)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package conditions
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Metav1UnspecifiedReason is used as metav1.Condition reason when a
+	// condition without reason is converted, because reason is mandatory in
+	// metav1.Condition. It is converted back to an empty reason.
+	Metav1UnspecifiedReason = "Unspecified"
+)
+
+// ToMetav1Condition converts Cluster API condition to metav1.Condition, as
+// used by newer Kubernetes APIs. Conversion is lossless, except for:
+//
+//   - Severity, which metav1.Condition does not have, so it is dropped.
+//   - Empty reason, which is not allowed in metav1.Condition, so it is set
+//     to Metav1UnspecifiedReason.
+//
+// ObservedGeneration, which Cluster API condition does not have, is set to
+// the specified generation.
+func ToMetav1Condition(condition capi.Condition, observedGeneration int64) metav1.Condition {
+	reason := condition.Reason
+	if reason == "" {
+		reason = Metav1UnspecifiedReason
+	}
+
+	return metav1.Condition{
+		Type:               string(condition.Type),
+		Status:             metav1.ConditionStatus(condition.Status),
+		ObservedGeneration: observedGeneration,
+		LastTransitionTime: condition.LastTransitionTime,
+		Reason:             reason,
+		Message:            condition.Message,
+	}
+}
+
+// FromMetav1Condition converts metav1.Condition to Cluster API condition.
+// Conversion is lossless, except for:
+//
+//   - ObservedGeneration, which Cluster API condition does not have, so it
+//     is dropped.
+//   - Reason Metav1UnspecifiedReason, which is converted to an empty reason.
+//
+// Severity, which metav1.Condition does not have, is not set, i.e. severity
+// checks like WithSeverityWarning are not successful for converted
+// conditions, while WithoutSeverity is.
+func FromMetav1Condition(condition metav1.Condition) capi.Condition {
+	reason := condition.Reason
+	if reason == Metav1UnspecifiedReason {
+		reason = ""
+	}
+
+	return capi.Condition{
+		Type:               capi.ConditionType(condition.Type),
+		Status:             corev1.ConditionStatus(condition.Status),
+		LastTransitionTime: condition.LastTransitionTime,
+		Reason:             reason,
+		Message:            condition.Message,
+	}
+}
+
+// ToMetav1Conditions converts Cluster API conditions to metav1.Condition
+// list, see ToMetav1Condition.
+func ToMetav1Conditions(conditions capi.Conditions, observedGeneration int64) []metav1.Condition {
+	if conditions == nil {
+		return nil
+	}
+
+	result := make([]metav1.Condition, 0, len(conditions))
+	for _, condition := range conditions {
+		result = append(result, ToMetav1Condition(condition, observedGeneration))
+	}
+
+	return result
+}
+
+// FromMetav1Conditions converts metav1.Condition list to Cluster API
+// conditions, see FromMetav1Condition.
+func FromMetav1Conditions(conditions []metav1.Condition) capi.Conditions {
+	if conditions == nil {
+		return nil
+	}
+
+	result := make(capi.Conditions, 0, len(conditions))
+	for _, condition := range conditions {
+		result = append(result, FromMetav1Condition(condition))
+	}
+
+	return result
+}
+
+// Metav1ConditionsObject wraps an object with metav1.Condition list and
+// implements Object over the list, so that all functions from this package
+// can be used with objects that use standard Kubernetes conditions.
+// Conditions are converted with FromMetav1Condition and ToMetav1Condition.
+//
+// When conditions are set, ObservedGeneration of conditions that have not
+// changed is kept, and ObservedGeneration of all other conditions is set to
+// the generation of the wrapped object.
+//
+// Example:
+//
+//    object := conditions.NewMetav1ConditionsObject(myApp, &myApp.Status.Conditions)
+//    if conditions.IsReadyTrue(object) {
+//        // ...
+//    }
+//
+type Metav1ConditionsObject struct {
+	client.Object
+
+	conditions *[]metav1.Condition
+}
+
+// NewMetav1ConditionsObject returns a new Metav1ConditionsObject that wraps
+// the specified object, whose conditions are stored in the specified list.
+func NewMetav1ConditionsObject(object client.Object, conditions *[]metav1.Condition) *Metav1ConditionsObject {
+	return &Metav1ConditionsObject{
+		Object:     object,
+		conditions: conditions,
+	}
+}
+
+// GetConditions returns converted conditions of the wrapped object.
+func (o *Metav1ConditionsObject) GetConditions() capi.Conditions {
+	return FromMetav1Conditions(*o.conditions)
+}
+
+// SetConditions converts and sets conditions on the wrapped object.
+func (o *Metav1ConditionsObject) SetConditions(conditions capi.Conditions) {
+	existing := map[string]metav1.Condition{}
+	for _, condition := range *o.conditions {
+		existing[condition.Type] = condition
+	}
+
+	result := make([]metav1.Condition, 0, len(conditions))
+	for _, condition := range conditions {
+		converted := ToMetav1Condition(condition, o.GetGeneration())
+		old, ok := existing[converted.Type]
+		if ok && areMetav1ConditionsEqual(old, converted) {
+			converted.ObservedGeneration = old.ObservedGeneration
+		}
+		result = append(result, converted)
+	}
+
+	*o.conditions = result
+}
+
+// areMetav1ConditionsEqual checks if two conditions are equal, ignoring their
+// ObservedGeneration.
+func areMetav1ConditionsEqual(c1, c2 metav1.Condition) bool {
+	return c1.Type == c2.Type &&
+		c1.Status == c2.Status &&
+		c1.Reason == c2.Reason &&
+		c1.LastTransitionTime.Equal(&c2.LastTransitionTime) &&
+		c1.Message == c2.Message
+}
@@ -0,0 +1,126 @@
+package conditions
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestMetav1ConditionConversion(t *testing.T) {
+	transitionTime := metav1.NewTime(time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	testCases := []struct {
+		name              string
+		condition         capi.Condition
+		expectedMetav1    metav1.Condition
+		expectedRoundTrip capi.Condition
+	}{
+		{
+			name: "case 0: Condition with reason is converted without severity",
+			condition: capi.Condition{
+				Type:               capi.ReadyCondition,
+				Status:             corev1.ConditionFalse,
+				Severity:           capi.ConditionSeverityWarning,
+				Reason:             "NotReady",
+				Message:            "Not ready yet",
+				LastTransitionTime: transitionTime,
+			},
+			expectedMetav1: metav1.Condition{
+				Type:               "Ready",
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: 3,
+				Reason:             "NotReady",
+				Message:            "Not ready yet",
+				LastTransitionTime: transitionTime,
+			},
+			expectedRoundTrip: capi.Condition{
+				Type:               capi.ReadyCondition,
+				Status:             corev1.ConditionFalse,
+				Reason:             "NotReady",
+				Message:            "Not ready yet",
+				LastTransitionTime: transitionTime,
+			},
+		},
+		{
+			name: "case 1: Condition without reason gets unspecified reason",
+			condition: capi.Condition{
+				Type:               Creating,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: transitionTime,
+			},
+			expectedMetav1: metav1.Condition{
+				Type:               "Creating",
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: 3,
+				Reason:             Metav1UnspecifiedReason,
+				LastTransitionTime: transitionTime,
+			},
+			expectedRoundTrip: capi.Condition{
+				Type:               Creating,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: transitionTime,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			converted := ToMetav1Condition(tc.condition, 3)
+			if converted != tc.expectedMetav1 {
+				t.Fatalf("expected %#v, got %#v", tc.expectedMetav1, converted)
+			}
+
+			roundTrip := FromMetav1Condition(converted)
+			if !AreEqual(&roundTrip, &tc.expectedRoundTrip) {
+				t.Fatalf("expected %s, got %s", sprintCondition(&tc.expectedRoundTrip), sprintCondition(&roundTrip))
+			}
+		})
+	}
+}
+
+func TestMetav1ConditionsConversionKeepsNil(t *testing.T) {
+	if ToMetav1Conditions(nil, 1) != nil || FromMetav1Conditions(nil) != nil {
+		t.Fatalf("expected nil conditions to be converted to nil")
+	}
+}
+
+func TestMetav1ConditionsObject(t *testing.T) {
+	oldTime := metav1.NewTime(time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC))
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test", Generation: 5}}
+	metav1Conditions := []metav1.Condition{
+		{Type: "Ready", Status: metav1.ConditionFalse, Reason: "NotReady", ObservedGeneration: 4, LastTransitionTime: oldTime},
+		{Type: "Creating", Status: metav1.ConditionTrue, Reason: Metav1UnspecifiedReason, ObservedGeneration: 4, LastTransitionTime: oldTime},
+	}
+	object := NewMetav1ConditionsObject(namespace, &metav1Conditions)
+
+	if !IsReadyFalse(object, WithReason("NotReady"), WithoutSeverity()) {
+		t.Fatalf("expected Ready condition with status False, got %s", sprintConditionForObject(object, capi.ReadyCondition))
+	}
+	if !IsCreatingTrue(object) {
+		t.Fatalf("expected Creating condition with status True, got %s", sprintConditionForObject(object, Creating))
+	}
+
+	capiconditions.MarkTrue(object, capi.ReadyCondition)
+
+	if len(metav1Conditions) != 2 {
+		t.Fatalf("expected 2 conditions, got %v", metav1Conditions)
+	}
+	for _, condition := range metav1Conditions {
+		switch condition.Type {
+		case "Ready":
+			if condition.Status != metav1.ConditionTrue || condition.ObservedGeneration != 5 || condition.Reason != Metav1UnspecifiedReason {
+				t.Fatalf("expected changed Ready condition with observed generation 5, got %#v", condition)
+			}
+		case "Creating":
+			if condition.ObservedGeneration != 4 {
+				t.Fatalf("expected unchanged Creating condition to keep observed generation 4, got %#v", condition)
+			}
+		}
+	}
+}
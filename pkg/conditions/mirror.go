@@ -0,0 +1,85 @@
+package conditions
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// mirrorSpec describes a condition that is set by mirroring Ready condition
+// from a referenced object, e.g. InfrastructureReady or ControlPlaneReady.
+// Severities of conditions set for missing reference, missing object and
+// missing Ready condition are default severities from the descriptor.
+type mirrorSpec struct {
+	descriptor            *ConditionDescriptor
+	description           string
+	referenceNotSetReason string
+	objectNotFoundReason  string
+	waitingReason         string
+	warningThresholdTime  time.Duration
+}
+
+// updateMirror sets the condition described by the specified spec on the
+// object, by mirroring Ready condition from the referenced object, and then
+// escalates its severity. It returns the duration after which the next
+// severity escalation is due.
+func updateMirror(object Object, spec mirrorSpec, reference *corev1.ObjectReference, referencedObject capiconditions.Getter, clock Clock) time.Duration {
+	var condition *capi.Condition
+	switch {
+	case reference == nil || reference.Name == "":
+		condition = spec.descriptor.falseCondition(
+			spec.referenceNotSetReason,
+			"%s reference is not set",
+			spec.description)
+	case isNilGetter(referencedObject):
+		condition = spec.descriptor.falseCondition(
+			spec.objectNotFoundReason,
+			"%s object %s is not found",
+			spec.description,
+			sprintReference(reference))
+	default:
+		condition = capiconditions.Get(referencedObject, capi.ReadyCondition)
+		if condition == nil {
+			condition = spec.descriptor.falseCondition(
+				spec.waitingReason,
+				"Waiting for %s object %s to have Ready condition",
+				spec.description,
+				sprintReference(reference))
+		}
+	}
+
+	// LastTransitionTime of the referenced object is not mirrored. It is
+	// cleared here, so that setCondition keeps the current LastTransitionTime
+	// of the mirrored condition while its status and reason do not change,
+	// and sets it from the clock when they change (or when the condition is
+	// not set yet). Severity escalation is based on this time.
+	condition.Type = spec.descriptor.Type
+	condition.LastTransitionTime = metav1.Time{}
+	setCondition(object, *condition, clock)
+
+	return escalateSeverity(object, spec.descriptor.Type, spec.warningThresholdTime, clock)
+}
+
+// isNilGetter checks if the specified getter is nil, including the case when
+// it is a nil pointer of a concrete type, e.g. (*capi.Cluster)(nil).
+func isNilGetter(getter capiconditions.Getter) bool {
+	if getter == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(getter)
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
+
+func sprintReference(reference *corev1.ObjectReference) string {
+	if reference.Namespace == "" {
+		return fmt.Sprintf("%s %s", reference.Kind, reference.Name)
+	}
+
+	return fmt.Sprintf("%s %s/%s", reference.Kind, reference.Namespace, reference.Name)
+}
@@ -1,7 +1,11 @@
 package conditions
 
 import (
+	"fmt"
+	"strings"
+
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
 )
 
@@ -15,37 +19,169 @@ const (
 	// NodePoolsReady is set with status False because node pool objects (e.g.
 	// MachinePool CRs or MachineDeployment CRs) are not found.
 	NodePoolsNotFoundReason = "NodePoolObjectsNotFound"
+
+	// NodePoolsNotReadyReason is a condition reason that is set when
+	// NodePoolsReady is set with status False because one or more node pools
+	// are not in Ready condition.
+	NodePoolsNotReadyReason = "NodePoolsNotReady"
 )
 
-// IsNodePoolsReadyTrue checks if specified cluster is in NodePoolsReady
+// nodePoolsReadyDescriptor describes NodePoolsReady condition.
+var nodePoolsReadyDescriptor = ConditionDescriptor{
+	Type: NodePoolsReady,
+	Reasons: map[string]capi.ConditionSeverity{
+		NodePoolsNotFoundReason: capi.ConditionSeverityWarning,
+		NodePoolsNotReadyReason: capi.ConditionSeverityInfo,
+	},
+	TargetKinds: []string{"Cluster"},
+}
+
+// NodePoolsReadyDescriptor returns a copy of the descriptor of NodePoolsReady
+// condition.
+func NodePoolsReadyDescriptor() ConditionDescriptor {
+	return nodePoolsReadyDescriptor.deepCopy()
+}
+
+// IsNodePoolsReadyTrue checks if specified object is in NodePoolsReady
 // condition (if NodePoolsReady condition is set with status True).
-func IsNodePoolsReadyTrue(cluster *capi.Cluster) bool {
-	return capiconditions.IsTrue(cluster, NodePoolsReady)
+func IsNodePoolsReadyTrue(object Object) bool {
+	return nodePoolsReadyDescriptor.IsTrue(object)
 }
 
 // IsNodePoolsReadyFalse checks if specified object is not in NodePoolsReady
 // condition (if NodePoolsReady condition is set with status False) and if
 // optionally specified checks are successful.
-func IsNodePoolsReadyFalse(cluster *capi.Cluster, checkOptions ...CheckOption) bool {
-	condition := capiconditions.Get(cluster, NodePoolsReady)
-	if !IsFalse(condition) {
-		// Condition is not set or it does not have status False
-		return false
+func IsNodePoolsReadyFalse(object Object, checkOptions ...CheckOption) bool {
+	return nodePoolsReadyDescriptor.IsFalse(object, checkOptions...)
+}
+
+// IsNodePoolsReadyUnknown checks if it is unknown whether the specified object
+// is in NodePoolsReady condition or not (if NodePoolsReady condition is not
+// set, or it is set with status Unknown).
+func IsNodePoolsReadyUnknown(object Object) bool {
+	return nodePoolsReadyDescriptor.IsUnknown(object)
+}
+
+// UpdateNodePoolsReady sets NodePoolsReady condition on the specified cluster
+// by aggregating conditions of its node pools, i.e. MachinePool and
+// MachineDeployment objects in the cluster namespace whose Spec.ClusterName
+// is set to the cluster name. Node pools from other clusters, including
+// clusters with the same name in other namespaces, are ignored.
+//
+// A node pool is ready when its Ready condition is set with status True, and
+// in case of MachinePool also when its ReplicasReady condition is not set
+// with status False. When all node pools are ready, NodePoolsReady is set
+// with status True. Otherwise it is set with status False, reason
+// NodePoolsNotReady, the highest severity of all node pool conditions that
+// are False (or the default severity for the reason if there are none, see
+// NodePoolsReadyDescriptor) and a message that lists not ready
+// node pools. When there are no node pools, NodePoolsReady is set with
+// status False, reason NodePoolObjectsNotFound and severity Warning.
+//
+// It returns node pools that are blocking cluster readiness. Current time is
+// taken from the clock set with WithClock.
+func UpdateNodePoolsReady(cluster *capi.Cluster, machinePools []capiexp.MachinePool, machineDeployments []capi.MachineDeployment, options ...TimeOption) []Object {
+	clock := clockOf(options)
+
+	var nodePools []nodePool
+	for i := range machinePools {
+		machinePool := &machinePools[i]
+		if !isNodePoolOf(cluster, machinePool, machinePool.Spec.ClusterName) {
+			continue
+		}
+
+		condition := capiconditions.Get(machinePool, capiexp.ReplicasReadyCondition)
+		if !IsFalse(condition) {
+			condition = capiconditions.Get(machinePool, capi.ReadyCondition)
+		}
+
+		nodePools = append(nodePools, nodePool{
+			kind:      "MachinePool",
+			object:    machinePool,
+			condition: condition,
+		})
 	}
 
-	for _, checkOption := range checkOptions {
-		if !checkOption(condition) {
-			// additional check has failed
-			return false
+	for i := range machineDeployments {
+		machineDeployment := &machineDeployments[i]
+		if !isNodePoolOf(cluster, machineDeployment, machineDeployment.Spec.ClusterName) {
+			continue
 		}
+
+		nodePools = append(nodePools, nodePool{
+			kind:      "MachineDeployment",
+			object:    machineDeployment,
+			condition: capiconditions.Get(machineDeployment, capi.ReadyCondition),
+		})
 	}
 
-	return true
+	if len(nodePools) == 0 {
+		condition := nodePoolsReadyDescriptor.falseCondition(
+			NodePoolsNotFoundReason,
+			"Node pool objects are not found for cluster %s",
+			cluster.Name)
+		setCondition(cluster, *condition, clock)
+		return nil
+	}
+
+	var blocking []Object
+	var notReadyDescriptions []string
+	severity, _ := nodePoolsReadyDescriptor.DefaultSeverity(NodePoolsNotReadyReason)
+	for _, np := range nodePools {
+		if IsTrue(np.condition) {
+			continue
+		}
+
+		blocking = append(blocking, np.object)
+		notReadyDescriptions = append(notReadyDescriptions, np.describe())
+
+		if IsFalse(np.condition) && severityRank(np.condition.Severity) > severityRank(severity) {
+			severity = np.condition.Severity
+		}
+	}
+
+	if len(blocking) == 0 {
+		setCondition(cluster, *capiconditions.TrueCondition(NodePoolsReady), clock)
+		return nil
+	}
+
+	condition := capiconditions.FalseCondition(
+		NodePoolsReady,
+		NodePoolsNotReadyReason,
+		severity,
+		"%d of %d node pools are ready, not ready: %s",
+		len(nodePools)-len(blocking),
+		len(nodePools),
+		strings.Join(notReadyDescriptions, ", "))
+	setCondition(cluster, *condition, clock)
+
+	return blocking
 }
 
-// IsNodePoolsReadyUnknown checks if it is unknown whether the specified cluster
-// is in NodePoolsReady condition or not (if NodePoolsReady condition is not
-// set, or it is set with status Unknown).
-func IsNodePoolsReadyUnknown(cluster *capi.Cluster) bool {
-	return capiconditions.IsUnknown(cluster, NodePoolsReady)
+// isNodePoolOf checks if the specified node pool object with the specified
+// cluster name belongs to the cluster. Cluster names are unique only within a
+// namespace, and node pools are always in the namespace of their cluster.
+func isNodePoolOf(cluster *capi.Cluster, object Object, clusterName string) bool {
+	return object.GetNamespace() == cluster.Namespace && clusterName == cluster.Name
+}
+
+// nodePool is a MachinePool or a MachineDeployment, together with its
+// condition that determines if the node pool is ready.
+type nodePool struct {
+	kind      string
+	object    Object
+	condition *capi.Condition
+}
+
+func (np nodePool) describe() string {
+	var state string
+	if np.condition == nil {
+		state = "Ready condition not set"
+	} else if np.condition.Reason != "" {
+		state = fmt.Sprintf("%s=%s, %s", np.condition.Type, np.condition.Status, np.condition.Reason)
+	} else {
+		state = fmt.Sprintf("%s=%s", np.condition.Type, np.condition.Status)
+	}
+
+	return fmt.Sprintf("%s %s (%s)", np.kind, np.object.GetName(), state)
 }
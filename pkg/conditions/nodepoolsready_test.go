@@ -2,9 +2,13 @@ package conditions
 
 import (
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
 )
 
 func TestIsNodePoolsReadyTrue(t *testing.T) {
@@ -144,3 +148,151 @@ func TestIsNodePoolsReadyUnknown(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateNodePoolsReady(t *testing.T) {
+	machinePool := func(name, clusterName string, conditions ...capi.Condition) capiexp.MachinePool {
+		return capiexp.MachinePool{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "org-test", Name: name},
+			Spec:       capiexp.MachinePoolSpec{ClusterName: clusterName},
+			Status:     capiexp.MachinePoolStatus{Conditions: conditions},
+		}
+	}
+	machineDeployment := func(name, clusterName string, conditions ...capi.Condition) capi.MachineDeployment {
+		return capi.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "org-test", Name: name},
+			Spec:       capi.MachineDeploymentSpec{ClusterName: clusterName},
+			Status:     capi.MachineDeploymentStatus{Conditions: conditions},
+		}
+	}
+	inNamespace := func(machineDeployment capi.MachineDeployment, namespace string) capi.MachineDeployment {
+		machineDeployment.Namespace = namespace
+		return machineDeployment
+	}
+	ready := capi.Condition{Type: capi.ReadyCondition, Status: corev1.ConditionTrue}
+	now := time.Date(2022, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name               string
+		machinePools       []capiexp.MachinePool
+		machineDeployments []capi.MachineDeployment
+		expectedStatus     corev1.ConditionStatus
+		expectedReason     string
+		expectedSeverity   capi.ConditionSeverity
+		expectedBlocking   []string
+	}{
+		{
+			name:             "case 0: NodePoolsReady is False with reason NodePoolObjectsNotFound when there are no node pools",
+			expectedStatus:   corev1.ConditionFalse,
+			expectedReason:   NodePoolsNotFoundReason,
+			expectedSeverity: capi.ConditionSeverityWarning,
+		},
+		{
+			name: "case 1: NodePoolsReady is False with reason NodePoolObjectsNotFound when node pools belong to other clusters",
+			machinePools: []capiexp.MachinePool{
+				machinePool("np1", "other", ready),
+			},
+			expectedStatus:   corev1.ConditionFalse,
+			expectedReason:   NodePoolsNotFoundReason,
+			expectedSeverity: capi.ConditionSeverityWarning,
+		},
+		{
+			name: "case 2: NodePoolsReady is True when all node pools are ready",
+			machinePools: []capiexp.MachinePool{
+				machinePool("np1", "test", ready),
+			},
+			machineDeployments: []capi.MachineDeployment{
+				machineDeployment("np2", "test", ready),
+			},
+			expectedStatus: corev1.ConditionTrue,
+		},
+		{
+			name: "case 3: NodePoolsReady is False when MachinePool replicas are not ready",
+			machinePools: []capiexp.MachinePool{
+				machinePool("np1", "test", ready, capi.Condition{
+					Type:     capiexp.ReplicasReadyCondition,
+					Status:   corev1.ConditionFalse,
+					Reason:   capiexp.WaitingForReplicasReadyReason,
+					Severity: capi.ConditionSeverityInfo,
+				}),
+				machinePool("np2", "test", ready),
+			},
+			expectedStatus:   corev1.ConditionFalse,
+			expectedReason:   NodePoolsNotReadyReason,
+			expectedSeverity: capi.ConditionSeverityInfo,
+			expectedBlocking: []string{"np1"},
+		},
+		{
+			name: "case 4: NodePoolsReady is False with the highest severity of not ready node pools",
+			machinePools: []capiexp.MachinePool{
+				machinePool("np1", "test", capi.Condition{
+					Type:     capi.ReadyCondition,
+					Status:   corev1.ConditionFalse,
+					Severity: capi.ConditionSeverityWarning,
+				}),
+			},
+			machineDeployments: []capi.MachineDeployment{
+				machineDeployment("np2", "test", capi.Condition{
+					Type:     capi.ReadyCondition,
+					Status:   corev1.ConditionFalse,
+					Severity: capi.ConditionSeverityError,
+				}),
+				machineDeployment("np3", "test", ready),
+				machineDeployment("np4", "test"),
+			},
+			expectedStatus:   corev1.ConditionFalse,
+			expectedReason:   NodePoolsNotReadyReason,
+			expectedSeverity: capi.ConditionSeverityError,
+			expectedBlocking: []string{"np1", "np2", "np4"},
+		},
+		{
+			name: "case 5: Node pools of a cluster with the same name in another namespace are ignored",
+			machinePools: []capiexp.MachinePool{
+				machinePool("np1", "test", ready),
+			},
+			machineDeployments: []capi.MachineDeployment{
+				inNamespace(machineDeployment("np2", "test"), "org-other"),
+			},
+			expectedStatus: corev1.ConditionTrue,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+			cluster := &capi.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "org-test", Name: "test"}}
+
+			blocking := UpdateNodePoolsReady(cluster, tc.machinePools, tc.machineDeployments, WithClock(NewFakeClock(now)))
+
+			condition := capiconditions.Get(cluster, NodePoolsReady)
+			if condition == nil ||
+				condition.Status != tc.expectedStatus ||
+				condition.Reason != tc.expectedReason ||
+				condition.Severity != tc.expectedSeverity {
+				t.Logf(
+					"expected NodePoolsReady(Status=%q, Reason=%q, Severity=%q), got %s",
+					tc.expectedStatus,
+					tc.expectedReason,
+					tc.expectedSeverity,
+					sprintCondition(condition))
+				t.Fail()
+			}
+			if condition != nil && !condition.LastTransitionTime.Time.Equal(now) {
+				t.Logf("expected LastTransitionTime %s, got %s", now, condition.LastTransitionTime)
+				t.Fail()
+			}
+
+			var blockingNames []string
+			for _, object := range blocking {
+				blockingNames = append(blockingNames, object.GetName())
+			}
+			if len(blockingNames) != len(tc.expectedBlocking) {
+				t.Fatalf("expected blocking node pools %v, got %v", tc.expectedBlocking, blockingNames)
+			}
+			for i := range blockingNames {
+				if blockingNames[i] != tc.expectedBlocking[i] {
+					t.Fatalf("expected blocking node pools %v, got %v", tc.expectedBlocking, blockingNames)
+				}
+			}
+		})
+	}
+}
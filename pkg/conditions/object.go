@@ -0,0 +1,22 @@
+package conditions
+
+import (
+	"reflect"
+)
+
+// KindOf returns the kind of the specified object. When object kind is not
+// set, which is usually the case for typed objects returned by the
+// controller-runtime client, the name of the object type is used.
+func KindOf(object Object) string {
+	kind := object.GetObjectKind().GroupVersionKind().Kind
+	if kind != "" {
+		return kind
+	}
+
+	t := reflect.TypeOf(object)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Name()
+}
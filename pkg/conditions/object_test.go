@@ -0,0 +1,38 @@
+package conditions
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestKindOf(t *testing.T) {
+	testCases := []struct {
+		name         string
+		object       Object
+		expectedKind string
+	}{
+		{
+			name:         "case 0: Kind is taken from type meta",
+			object:       &capi.Cluster{TypeMeta: metav1.TypeMeta{Kind: "AzureCluster"}},
+			expectedKind: "AzureCluster",
+		},
+		{
+			name:         "case 1: Kind is taken from object type when type meta is not set",
+			object:       machinePoolWithoutConditions(),
+			expectedKind: "MachinePool",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			kind := KindOf(tc.object)
+			if kind != tc.expectedKind {
+				t.Fatalf("expected kind %s, got %s", tc.expectedKind, kind)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package conditions
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	// ReadySummaryUnknownReason is a condition reason that is set when Ready
+	// is set with status Unknown by UpdateReady, because no summarized
+	// condition is False, but some of them are not set or they are set with
+	// status Unknown.
+	ReadySummaryUnknownReason = "ConditionsUnknown"
+)
+
+// defaultReadySummaryConditions contains conditions that are summarized into
+// Ready by default, ordered by their importance.
+var defaultReadySummaryConditions = []capi.ConditionType{
+	InfrastructureReady,
+	ControlPlaneReady,
+	NodePoolsReady,
+}
+
+type readySummaryCondition struct {
+	conditionType capi.ConditionType
+	weight        int
+}
+
+type readySummaryOptions struct {
+	timeOptions
+
+	conditions           []readySummaryCondition
+	ignoreDuringCreation map[capi.ConditionType]bool
+	ignoreDuringUpgrade  map[capi.ConditionType]bool
+	stepCounter          bool
+}
+
+// ReadySummaryOption is an option for ReadySummary and UpdateReady.
+// TimeOption can be used as ReadySummaryOption too, e.g. WithClock sets the
+// clock used for Ready LastTransitionTime in UpdateReady.
+type ReadySummaryOption interface {
+	applyToReadySummary(options *readySummaryOptions)
+}
+
+type readySummaryOptionFunc func(options *readySummaryOptions)
+
+func (f readySummaryOptionFunc) applyToReadySummary(options *readySummaryOptions) {
+	f(options)
+}
+
+func (o TimeOption) applyToReadySummary(options *readySummaryOptions) {
+	o(&options.timeOptions)
+}
+
+// WithSummaryConditions returns a ReadySummaryOption that sets conditions
+// that are summarized into Ready, ordered by their importance, all with
+// weight 1. By default, InfrastructureReady, ControlPlaneReady and
+// NodePoolsReady are summarized.
+func WithSummaryConditions(conditionTypes ...capi.ConditionType) ReadySummaryOption {
+	return readySummaryOptionFunc(func(options *readySummaryOptions) {
+		options.conditions = nil
+		for _, conditionType := range conditionTypes {
+			options.conditions = append(options.conditions, readySummaryCondition{conditionType: conditionType, weight: 1})
+		}
+	})
+}
+
+// WithSummaryWeight returns a ReadySummaryOption that sets the weight of the
+// specified condition, adding the condition to summarized conditions if it
+// is not there yet. Weight is the number of steps that the condition counts
+// for in the step counter, and when multiple conditions are False with the
+// same severity, the one with the highest weight determines Ready reason.
+// Weight 0 or less removes the condition from summarized conditions.
+func WithSummaryWeight(conditionType capi.ConditionType, weight int) ReadySummaryOption {
+	return readySummaryOptionFunc(func(options *readySummaryOptions) {
+		var result []readySummaryCondition
+		found := false
+		for _, condition := range options.conditions {
+			if condition.conditionType == conditionType {
+				found = true
+				condition.weight = weight
+			}
+			if condition.weight > 0 {
+				result = append(result, condition)
+			}
+		}
+		if !found && weight > 0 {
+			result = append(result, readySummaryCondition{conditionType: conditionType, weight: weight})
+		}
+
+		options.conditions = result
+	})
+}
+
+// WithIgnoredDuringCreation returns a ReadySummaryOption that excludes the
+// specified conditions from the summary while the object is being created,
+// i.e. while Creating condition is set with status True. For example,
+// NodePoolsReady can be ignored while node pools are still being created.
+func WithIgnoredDuringCreation(conditionTypes ...capi.ConditionType) ReadySummaryOption {
+	return readySummaryOptionFunc(func(options *readySummaryOptions) {
+		for _, conditionType := range conditionTypes {
+			options.ignoreDuringCreation[conditionType] = true
+		}
+	})
+}
+
+// WithIgnoredDuringUpgrade returns a ReadySummaryOption that excludes the
+// specified conditions from the summary while the object is being upgraded,
+// i.e. while Upgrading condition is set with status True.
+func WithIgnoredDuringUpgrade(conditionTypes ...capi.ConditionType) ReadySummaryOption {
+	return readySummaryOptionFunc(func(options *readySummaryOptions) {
+		for _, conditionType := range conditionTypes {
+			options.ignoreDuringUpgrade[conditionType] = true
+		}
+	})
+}
+
+// WithSummaryStepCounter returns a ReadySummaryOption that sets Ready
+// message to the step counter, e.g. "2 of 3 completed", where every
+// summarized condition with status True counts for as many steps as its
+// weight.
+func WithSummaryStepCounter() ReadySummaryOption {
+	return readySummaryOptionFunc(func(options *readySummaryOptions) {
+		options.stepCounter = true
+	})
+}
+
+// ReadySummary computes Ready condition of the specified object from its
+// InfrastructureReady, ControlPlaneReady and NodePoolsReady conditions (or
+// other conditions set with options), taking into account whether the
+// object is being created or upgraded (see WithIgnoredDuringCreation and
+// WithIgnoredDuringUpgrade). The object is not changed.
+//
+// Ready is computed in the following way:
+//
+//   - When any summarized condition is False, Ready is False, with reason,
+//     severity and message of the most severe False condition. When
+//     multiple conditions have the same severity, the one with the highest
+//     weight is used, and then the one that comes first.
+//   - Otherwise, when any summarized condition is not set or it is set with
+//     status Unknown, Ready is Unknown with reason ConditionsUnknown.
+//   - Otherwise, Ready is True.
+//
+// Reason and severity are copied from the summarized condition, so the
+// result can be checked with IsReadyFalse, e.g. with
+// WithReason(NodePoolsNotReadyReason).
+func ReadySummary(object Object, options ...ReadySummaryOption) capi.Condition {
+	summaryOpts := newReadySummaryOptions(options...)
+
+	creating := IsCreatingTrue(object)
+	upgrading := IsUpgradingTrue(object)
+
+	var falseCondition *capi.Condition
+	var falseWeight int
+	var unknownTypes []string
+	var completed, total int
+	for _, summarized := range summaryOpts.conditions {
+		if creating && summaryOpts.ignoreDuringCreation[summarized.conditionType] ||
+			upgrading && summaryOpts.ignoreDuringUpgrade[summarized.conditionType] {
+			continue
+		}
+
+		total += summarized.weight
+		condition := capiconditions.Get(object, summarized.conditionType)
+		switch {
+		case IsTrue(condition):
+			completed += summarized.weight
+		case IsFalse(condition):
+			if falseCondition == nil ||
+				severityRank(condition.Severity) > severityRank(falseCondition.Severity) ||
+				severityRank(condition.Severity) == severityRank(falseCondition.Severity) && summarized.weight > falseWeight {
+				falseCondition = condition
+				falseWeight = summarized.weight
+			}
+		default:
+			unknownTypes = append(unknownTypes, string(summarized.conditionType))
+		}
+	}
+
+	var ready capi.Condition
+	switch {
+	case falseCondition != nil:
+		ready = capi.Condition{
+			Type:     capi.ReadyCondition,
+			Status:   corev1.ConditionFalse,
+			Reason:   falseCondition.Reason,
+			Severity: falseCondition.Severity,
+			Message:  falseCondition.Message,
+		}
+	case len(unknownTypes) > 0:
+		ready = capi.Condition{
+			Type:    capi.ReadyCondition,
+			Status:  corev1.ConditionUnknown,
+			Reason:  ReadySummaryUnknownReason,
+			Message: fmt.Sprintf("Conditions are not known: %s", strings.Join(unknownTypes, ", ")),
+		}
+	default:
+		ready = capi.Condition{
+			Type:   capi.ReadyCondition,
+			Status: corev1.ConditionTrue,
+		}
+	}
+
+	if summaryOpts.stepCounter && total > 0 {
+		ready.Message = fmt.Sprintf("%d of %d completed", completed, total)
+	}
+
+	return ready
+}
+
+// UpdateReady sets Ready condition on the specified object to the condition
+// computed by ReadySummary. LastTransitionTime is changed only when Ready
+// status or reason changes. Current time is taken from the clock set with
+// WithClock.
+//
+// Example:
+//
+//    conditions.UpdateReady(cluster,
+//        conditions.WithIgnoredDuringCreation(conditions.NodePoolsReady),
+//        conditions.WithSummaryWeight(conditions.ControlPlaneReady, 2),
+//        conditions.WithSummaryStepCounter())
+//
+func UpdateReady(object Object, options ...ReadySummaryOption) {
+	summaryOpts := newReadySummaryOptions(options...)
+	setCondition(object, ReadySummary(object, options...), summaryOpts.clockOrDefault())
+}
+
+func newReadySummaryOptions(options ...ReadySummaryOption) readySummaryOptions {
+	summaryOpts := readySummaryOptions{
+		ignoreDuringCreation: map[capi.ConditionType]bool{},
+		ignoreDuringUpgrade:  map[capi.ConditionType]bool{},
+	}
+	for _, conditionType := range defaultReadySummaryConditions {
+		summaryOpts.conditions = append(summaryOpts.conditions, readySummaryCondition{conditionType: conditionType, weight: 1})
+	}
+
+	for _, option := range options {
+		option.applyToReadySummary(&summaryOpts)
+	}
+
+	return summaryOpts
+}
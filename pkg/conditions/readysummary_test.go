@@ -0,0 +1,154 @@
+package conditions
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReadySummary(t *testing.T) {
+	infrastructureReady := capi.Condition{Type: InfrastructureReady, Status: corev1.ConditionTrue}
+	controlPlaneReady := capi.Condition{Type: ControlPlaneReady, Status: corev1.ConditionTrue}
+	nodePoolsReady := capi.Condition{Type: NodePoolsReady, Status: corev1.ConditionTrue}
+	nodePoolsNotReady := capi.Condition{Type: NodePoolsReady, Status: corev1.ConditionFalse, Reason: NodePoolsNotReadyReason, Severity: capi.ConditionSeverityInfo, Message: "0 of 1 node pools are ready"}
+	controlPlaneNotFound := capi.Condition{Type: ControlPlaneReady, Status: corev1.ConditionFalse, Reason: ControlPlaneObjectNotFoundReason, Severity: capi.ConditionSeverityWarning}
+	infrastructureWaiting := capi.Condition{Type: InfrastructureReady, Status: corev1.ConditionFalse, Reason: capi.WaitingForInfrastructureFallbackReason, Severity: capi.ConditionSeverityInfo}
+	creating := capi.Condition{Type: Creating, Status: corev1.ConditionTrue}
+	upgrading := capi.Condition{Type: Upgrading, Status: corev1.ConditionTrue}
+
+	testCases := []struct {
+		name            string
+		conditions      capi.Conditions
+		options         []ReadySummaryOption
+		expectedStatus  corev1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:           "case 0: Ready is True when all conditions are True",
+			conditions:     capi.Conditions{infrastructureReady, controlPlaneReady, nodePoolsReady},
+			expectedStatus: corev1.ConditionTrue,
+		},
+		{
+			name:            "case 1: Ready is False with reason and message of False condition",
+			conditions:      capi.Conditions{infrastructureReady, controlPlaneReady, nodePoolsNotReady},
+			expectedStatus:  corev1.ConditionFalse,
+			expectedReason:  NodePoolsNotReadyReason,
+			expectedMessage: "0 of 1 node pools are ready",
+		},
+		{
+			name:           "case 2: Most severe False condition is used",
+			conditions:     capi.Conditions{infrastructureWaiting, controlPlaneNotFound, nodePoolsNotReady},
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: ControlPlaneObjectNotFoundReason,
+		},
+		{
+			name:           "case 3: False condition with higher weight is used when severities are the same",
+			conditions:     capi.Conditions{infrastructureWaiting, controlPlaneReady, nodePoolsNotReady},
+			options:        []ReadySummaryOption{WithSummaryWeight(NodePoolsReady, 2)},
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: NodePoolsNotReadyReason,
+		},
+		{
+			name:           "case 4: First False condition is used when severities and weights are the same",
+			conditions:     capi.Conditions{infrastructureWaiting, controlPlaneReady, nodePoolsNotReady},
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: capi.WaitingForInfrastructureFallbackReason,
+		},
+		{
+			name:            "case 5: Ready is Unknown when a condition is not set",
+			conditions:      capi.Conditions{infrastructureReady, controlPlaneReady},
+			expectedStatus:  corev1.ConditionUnknown,
+			expectedReason:  ReadySummaryUnknownReason,
+			expectedMessage: "Conditions are not known: NodePoolsReady",
+		},
+		{
+			name:           "case 6: Condition ignored during creation is ignored while creating",
+			conditions:     capi.Conditions{creating, infrastructureReady, controlPlaneReady, nodePoolsNotReady},
+			options:        []ReadySummaryOption{WithIgnoredDuringCreation(NodePoolsReady)},
+			expectedStatus: corev1.ConditionTrue,
+		},
+		{
+			name:           "case 7: Condition ignored during creation is not ignored after creation",
+			conditions:     capi.Conditions{infrastructureReady, controlPlaneReady, nodePoolsNotReady},
+			options:        []ReadySummaryOption{WithIgnoredDuringCreation(NodePoolsReady)},
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: NodePoolsNotReadyReason,
+		},
+		{
+			name:           "case 8: Condition ignored during upgrade is ignored while upgrading",
+			conditions:     capi.Conditions{upgrading, infrastructureReady, controlPlaneNotFound, nodePoolsReady},
+			options:        []ReadySummaryOption{WithIgnoredDuringUpgrade(ControlPlaneReady)},
+			expectedStatus: corev1.ConditionTrue,
+		},
+		{
+			name:            "case 9: Step counter uses weights",
+			conditions:      capi.Conditions{infrastructureReady, controlPlaneReady, nodePoolsNotReady},
+			options:         []ReadySummaryOption{WithSummaryWeight(ControlPlaneReady, 3), WithSummaryStepCounter()},
+			expectedStatus:  corev1.ConditionFalse,
+			expectedReason:  NodePoolsNotReadyReason,
+			expectedMessage: "4 of 5 completed",
+		},
+		{
+			name:            "case 10: Summarized conditions can be changed",
+			conditions:      capi.Conditions{infrastructureReady, controlPlaneNotFound},
+			options:         []ReadySummaryOption{WithSummaryConditions(InfrastructureReady), WithSummaryStepCounter()},
+			expectedStatus:  corev1.ConditionTrue,
+			expectedMessage: "1 of 1 completed",
+		},
+		{
+			name:           "case 11: Condition with weight 0 is not summarized",
+			conditions:     capi.Conditions{infrastructureReady, controlPlaneNotFound, nodePoolsReady},
+			options:        []ReadySummaryOption{WithSummaryWeight(ControlPlaneReady, 0)},
+			expectedStatus: corev1.ConditionTrue,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+			cluster := clusterWithoutConditions()
+			cluster.Status.Conditions = tc.conditions
+
+			ready := ReadySummary(cluster, tc.options...)
+
+			if ready.Type != capi.ReadyCondition || ready.Status != tc.expectedStatus || ready.Reason != tc.expectedReason {
+				t.Fatalf("expected Ready with status %s and reason %q, got %s", tc.expectedStatus, tc.expectedReason, sprintCondition(&ready))
+			}
+			if tc.expectedMessage != "" && ready.Message != tc.expectedMessage {
+				t.Fatalf("expected message %q, got %q", tc.expectedMessage, ready.Message)
+			}
+		})
+	}
+}
+
+func TestUpdateReady(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(now)
+	cluster := clusterWithoutConditions()
+	cluster.Status.Conditions = capi.Conditions{
+		{Type: InfrastructureReady, Status: corev1.ConditionTrue},
+		{Type: ControlPlaneReady, Status: corev1.ConditionFalse, Reason: ControlPlaneObjectNotFoundReason, Severity: capi.ConditionSeverityWarning},
+		{Type: NodePoolsReady, Status: corev1.ConditionTrue},
+	}
+
+	UpdateReady(cluster, WithClock(clock))
+	if !IsReadyFalse(cluster, WithReason(ControlPlaneObjectNotFoundReason), WithSeverityWarning()) {
+		t.Fatalf("unexpected %s", sprintConditionForObject(cluster, capi.ReadyCondition))
+	}
+
+	// Message change does not change LastTransitionTime.
+	clock.Step(time.Minute)
+	UpdateReady(cluster, WithClock(clock), WithSummaryStepCounter())
+	lastTransitionTime := capiconditions.GetLastTransitionTime(cluster, capi.ReadyCondition)
+	if !lastTransitionTime.Equal(&metav1.Time{Time: now}) {
+		t.Fatalf("expected LastTransitionTime %s, got %s", now, lastTransitionTime)
+	}
+	if cluster.Status.Conditions[0].Type != capi.ReadyCondition {
+		t.Fatalf("expected Ready to be the first condition, got %s", cluster.Status.Conditions[0].Type)
+	}
+}
@@ -0,0 +1,71 @@
+package conditions
+
+import (
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// setCondition sets the specified condition on the object. As opposed to
+// capiconditions.Set, LastTransitionTime is updated only when the condition
+// status or reason changes, so that changing only severity or message (e.g.
+// when severity is escalated) does not reset the time since when the
+// condition is in its current state. New LastTransitionTime is taken from
+// the specified clock.
+func setCondition(object Object, condition capi.Condition, clock Clock) {
+	conditions := object.GetConditions()
+
+	exists := false
+	for i := range conditions {
+		if conditions[i].Type != condition.Type {
+			continue
+		}
+
+		exists = true
+		if conditions[i].Status == condition.Status && conditions[i].Reason == condition.Reason {
+			condition.LastTransitionTime = conditions[i].LastTransitionTime
+		} else {
+			condition.LastTransitionTime = now(clock)
+		}
+		conditions[i] = condition
+		break
+	}
+
+	if !exists {
+		if condition.LastTransitionTime.IsZero() {
+			condition.LastTransitionTime = now(clock)
+		}
+		conditions = append(conditions, condition)
+	}
+
+	// Keep the same order as capiconditions.Set, i.e. Ready condition first
+	// and all other conditions sorted by type.
+	sort.Slice(conditions, func(i, j int) bool {
+		return (conditions[i].Type == capi.ReadyCondition || conditions[i].Type < conditions[j].Type) &&
+			conditions[j].Type != capi.ReadyCondition
+	})
+
+	object.SetConditions(conditions)
+}
+
+// updateCondition changes the condition of the specified type in place,
+// without changing its LastTransitionTime. It does nothing if the condition
+// is not set.
+func updateCondition(object Object, conditionType capi.ConditionType, update func(condition *capi.Condition)) {
+	conditions := object.GetConditions()
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			update(&conditions[i])
+		}
+	}
+
+	object.SetConditions(conditions)
+}
+
+// now returns the current time from the clock, in the same format as
+// capiconditions.Set uses for LastTransitionTime.
+func now(clock Clock) metav1.Time {
+	return metav1.NewTime(clock.Now().UTC().Truncate(time.Second))
+}
@@ -0,0 +1,103 @@
+package conditions
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestSetCondition(t *testing.T) {
+	before := time.Date(2021, 1, 1, 11, 0, 0, 0, time.UTC)
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name                       string
+		condition                  capi.Condition
+		expectedLastTransitionTime time.Time
+	}{
+		{
+			name: "case 0: LastTransitionTime is preserved when only severity and message are changed",
+			condition: capi.Condition{
+				Type:     Creating,
+				Status:   corev1.ConditionFalse,
+				Reason:   CreationCompletedReason,
+				Severity: capi.ConditionSeverityWarning,
+				Message:  "Changed",
+			},
+			expectedLastTransitionTime: before,
+		},
+		{
+			name: "case 1: LastTransitionTime is updated when reason is changed",
+			condition: capi.Condition{
+				Type:     Creating,
+				Status:   corev1.ConditionFalse,
+				Reason:   ExistingObjectReason,
+				Severity: capi.ConditionSeverityInfo,
+			},
+			expectedLastTransitionTime: now,
+		},
+		{
+			name: "case 2: LastTransitionTime is updated when status is changed",
+			condition: capi.Condition{
+				Type:   Creating,
+				Status: corev1.ConditionTrue,
+			},
+			expectedLastTransitionTime: now,
+		},
+		{
+			name: "case 3: LastTransitionTime is set for new condition",
+			condition: capi.Condition{
+				Type:   Upgrading,
+				Status: corev1.ConditionTrue,
+			},
+			expectedLastTransitionTime: now,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+			cluster := &capi.Cluster{
+				Status: capi.ClusterStatus{
+					Conditions: capi.Conditions{
+						{
+							Type:               capi.ReadyCondition,
+							Status:             corev1.ConditionTrue,
+							LastTransitionTime: metav1.NewTime(before),
+						},
+						{
+							Type:               Creating,
+							Status:             corev1.ConditionFalse,
+							Reason:             CreationCompletedReason,
+							Severity:           capi.ConditionSeverityInfo,
+							LastTransitionTime: metav1.NewTime(before),
+						},
+					},
+				},
+			}
+
+			setCondition(cluster, tc.condition, NewFakeClock(now))
+
+			condition := capiconditions.Get(cluster, tc.condition.Type)
+			tc.condition.LastTransitionTime = condition.LastTransitionTime
+			if !AreEqual(condition, &tc.condition) {
+				t.Logf("expected %s, got %s", sprintCondition(&tc.condition), sprintCondition(condition))
+				t.Fail()
+			}
+
+			if !condition.LastTransitionTime.Time.Equal(tc.expectedLastTransitionTime) {
+				t.Logf("expected LastTransitionTime %s, got %s", tc.expectedLastTransitionTime, condition.LastTransitionTime)
+				t.Fail()
+			}
+
+			if cluster.Status.Conditions[0].Type != capi.ReadyCondition {
+				t.Logf("expected Ready to be the first condition, got %s", cluster.Status.Conditions[0].Type)
+				t.Fail()
+			}
+		})
+	}
+}
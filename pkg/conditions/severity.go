@@ -0,0 +1,115 @@
+package conditions
+
+import (
+	"time"
+
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// warningThresholdTimes contains waiting times during which conditions are
+// set to False with severity Info, after which they are escalated to
+// severity Warning.
+var warningThresholdTimes = map[capi.ConditionType]time.Duration{
+	ControlPlaneReady:   WaitingForControlPlaneWarningThresholdTime,
+	InfrastructureReady: WaitingForInfrastructureWarningThresholdTime,
+}
+
+// WarningThresholdTime returns waiting time during which the condition of the
+// specified type is set to False with severity Info, and true. If there is
+// no threshold time for the condition type, it returns 0 and false.
+func WarningThresholdTime(conditionType capi.ConditionType) (time.Duration, bool) {
+	threshold, ok := warningThresholdTimes[conditionType]
+	return threshold, ok
+}
+
+// EscalateSeverity updates the severity of the specified condition based on
+// how long the condition has been set to False, i.e. it sets severity Info
+// while the condition is within its warning threshold time (see
+// WarningThresholdTime) and severity Warning afterwards. LastTransitionTime
+// of the condition is not changed.
+//
+// It returns the duration after which the next escalation is due, which can
+// be used as RequeueAfter value in the controller result. If no escalation
+// is due, e.g. because the condition is not set to False, because it already
+// has severity Warning or Error, because it has no LastTransitionTime, or
+// because the condition type has no threshold time, it returns 0. Current
+// time is taken from the clock set with WithClock.
+//
+// Example:
+//
+//    requeueAfter := conditions.EscalateSeverity(cluster, conditions.InfrastructureReady)
+//    return reconcile.Result{RequeueAfter: requeueAfter}, nil
+//
+func EscalateSeverity(object Object, conditionType capi.ConditionType, options ...TimeOption) time.Duration {
+	threshold, ok := WarningThresholdTime(conditionType)
+	if !ok {
+		return 0
+	}
+
+	return escalateSeverity(object, conditionType, threshold, clockOf(options))
+}
+
+// IsWarningThresholdExceeded checks if the condition of the specified type
+// is set to False for longer than its warning threshold time (see
+// WarningThresholdTime). It returns false for condition types without
+// threshold time and for conditions without LastTransitionTime. Current
+// time is taken from the clock set with WithClock.
+func IsWarningThresholdExceeded(object Object, conditionType capi.ConditionType, options ...TimeOption) bool {
+	threshold, ok := WarningThresholdTime(conditionType)
+	if !ok {
+		return false
+	}
+
+	condition := capiconditions.Get(object, conditionType)
+	return IsFalse(condition) && !condition.LastTransitionTime.IsZero() && clockOf(options).Now().Sub(condition.LastTransitionTime.Time) >= threshold
+}
+
+func escalateSeverity(object Object, conditionType capi.ConditionType, threshold time.Duration, clock Clock) time.Duration {
+	condition := capiconditions.Get(object, conditionType)
+	if !IsFalse(condition) {
+		return 0
+	}
+
+	// We do not de-escalate severity. Warning and Error are either already
+	// escalated or they were explicitly set.
+	if condition.Severity != capi.ConditionSeverityNone && condition.Severity != capi.ConditionSeverityInfo {
+		return 0
+	}
+
+	// Without LastTransitionTime we cannot tell how long the condition has
+	// been set to False, so it is not escalated.
+	if condition.LastTransitionTime.IsZero() {
+		return 0
+	}
+
+	waitingTime := clock.Now().Sub(condition.LastTransitionTime.Time)
+	severity := capi.ConditionSeverityInfo
+	var requeueAfter time.Duration
+	if waitingTime >= threshold {
+		severity = capi.ConditionSeverityWarning
+	} else {
+		requeueAfter = threshold - waitingTime
+	}
+
+	// Severity is changed in place, so that LastTransitionTime is preserved.
+	updateCondition(object, conditionType, func(condition *capi.Condition) {
+		condition.Severity = severity
+	})
+	return requeueAfter
+}
+
+// severityRank returns a number that can be used to compare condition
+// severities, where higher number means more severe condition.
+func severityRank(severity capi.ConditionSeverity) int {
+	switch severity {
+	case capi.ConditionSeverityError:
+		return 3
+	case capi.ConditionSeverityWarning:
+		return 2
+	case capi.ConditionSeverityInfo:
+		return 1
+	default:
+		return 0
+	}
+}
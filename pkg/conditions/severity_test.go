@@ -0,0 +1,207 @@
+package conditions
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestEscalateSeverity(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name                 string
+		conditionType        capi.ConditionType
+		condition            *capi.Condition
+		expectedSeverity     capi.ConditionSeverity
+		expectedRequeueAfter time.Duration
+	}{
+		{
+			name:          "case 0: Severity Info is kept within threshold time",
+			conditionType: InfrastructureReady,
+			condition: &capi.Condition{
+				Type:               InfrastructureReady,
+				Status:             corev1.ConditionFalse,
+				Severity:           capi.ConditionSeverityInfo,
+				LastTransitionTime: metav1.NewTime(now.Add(-4 * time.Minute)),
+			},
+			expectedSeverity:     capi.ConditionSeverityInfo,
+			expectedRequeueAfter: 6 * time.Minute,
+		},
+		{
+			name:          "case 1: Severity Info is escalated to Warning after threshold time",
+			conditionType: ControlPlaneReady,
+			condition: &capi.Condition{
+				Type:               ControlPlaneReady,
+				Status:             corev1.ConditionFalse,
+				Severity:           capi.ConditionSeverityInfo,
+				LastTransitionTime: metav1.NewTime(now.Add(-15 * time.Minute)),
+			},
+			expectedSeverity:     capi.ConditionSeverityWarning,
+			expectedRequeueAfter: 0,
+		},
+		{
+			name:          "case 2: Missing severity is set to Info within threshold time",
+			conditionType: InfrastructureReady,
+			condition: &capi.Condition{
+				Type:               InfrastructureReady,
+				Status:             corev1.ConditionFalse,
+				LastTransitionTime: metav1.NewTime(now),
+			},
+			expectedSeverity:     capi.ConditionSeverityInfo,
+			expectedRequeueAfter: WaitingForInfrastructureWarningThresholdTime,
+		},
+		{
+			name:          "case 3: Severity Error is not changed",
+			conditionType: InfrastructureReady,
+			condition: &capi.Condition{
+				Type:               InfrastructureReady,
+				Status:             corev1.ConditionFalse,
+				Severity:           capi.ConditionSeverityError,
+				LastTransitionTime: metav1.NewTime(now),
+			},
+			expectedSeverity:     capi.ConditionSeverityError,
+			expectedRequeueAfter: 0,
+		},
+		{
+			name:          "case 4: Condition with status True is not changed",
+			conditionType: ControlPlaneReady,
+			condition: &capi.Condition{
+				Type:               ControlPlaneReady,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(now.Add(-time.Hour)),
+			},
+			expectedSeverity:     capi.ConditionSeverityNone,
+			expectedRequeueAfter: 0,
+		},
+		{
+			name:          "case 5: Condition without threshold time is not changed",
+			conditionType: Creating,
+			condition: &capi.Condition{
+				Type:               Creating,
+				Status:             corev1.ConditionFalse,
+				Severity:           capi.ConditionSeverityInfo,
+				LastTransitionTime: metav1.NewTime(now.Add(-time.Hour)),
+			},
+			expectedSeverity:     capi.ConditionSeverityInfo,
+			expectedRequeueAfter: 0,
+		},
+		{
+			name:                 "case 6: Nothing happens when condition is not set",
+			conditionType:        InfrastructureReady,
+			expectedRequeueAfter: 0,
+		},
+		{
+			name:          "case 7: Condition without LastTransitionTime is not escalated",
+			conditionType: InfrastructureReady,
+			condition: &capi.Condition{
+				Type:     InfrastructureReady,
+				Status:   corev1.ConditionFalse,
+				Severity: capi.ConditionSeverityInfo,
+			},
+			expectedSeverity:     capi.ConditionSeverityInfo,
+			expectedRequeueAfter: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+			cluster := clusterWithoutConditions()
+			if tc.condition != nil {
+				cluster.Status.Conditions = capi.Conditions{*tc.condition}
+			}
+
+			requeueAfter := EscalateSeverity(cluster, tc.conditionType, WithClock(NewFakeClock(now)))
+
+			if requeueAfter != tc.expectedRequeueAfter {
+				t.Logf("expected RequeueAfter %s, got %s", tc.expectedRequeueAfter, requeueAfter)
+				t.Fail()
+			}
+
+			condition := capiconditions.Get(cluster, tc.conditionType)
+			if tc.condition == nil {
+				if condition != nil {
+					t.Logf("expected condition not to be set, got %s", sprintCondition(condition))
+					t.Fail()
+				}
+				return
+			}
+
+			if condition.Severity != tc.expectedSeverity {
+				t.Logf("expected severity %q, got %s", tc.expectedSeverity, sprintCondition(condition))
+				t.Fail()
+			}
+
+			if !condition.LastTransitionTime.Equal(&tc.condition.LastTransitionTime) {
+				t.Logf("expected LastTransitionTime not to be changed, got %s", condition.LastTransitionTime)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestIsWarningThresholdExceeded(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		conditionType  capi.ConditionType
+		status         corev1.ConditionStatus
+		transitionAgo  time.Duration
+		expectedOutput bool
+	}{
+		{
+			name:           "case 0: False condition within threshold time is not exceeded",
+			conditionType:  InfrastructureReady,
+			status:         corev1.ConditionFalse,
+			transitionAgo:  4 * time.Minute,
+			expectedOutput: false,
+		},
+		{
+			name:           "case 1: False condition after threshold time is exceeded",
+			conditionType:  ControlPlaneReady,
+			status:         corev1.ConditionFalse,
+			transitionAgo:  WaitingForControlPlaneWarningThresholdTime,
+			expectedOutput: true,
+		},
+		{
+			name:           "case 2: True condition is never exceeded",
+			conditionType:  InfrastructureReady,
+			status:         corev1.ConditionTrue,
+			transitionAgo:  time.Hour,
+			expectedOutput: false,
+		},
+		{
+			name:           "case 3: Condition without threshold time is never exceeded",
+			conditionType:  capi.ReadyCondition,
+			status:         corev1.ConditionFalse,
+			transitionAgo:  time.Hour,
+			expectedOutput: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+			cluster := clusterWithoutConditions()
+			cluster.Status.Conditions = capi.Conditions{
+				{
+					Type:               tc.conditionType,
+					Status:             tc.status,
+					LastTransitionTime: metav1.NewTime(now.Add(-tc.transitionAgo)),
+				},
+			}
+
+			result := IsWarningThresholdExceeded(cluster, tc.conditionType, WithClock(NewFakeClock(now)))
+			if result != tc.expectedOutput {
+				t.Logf("expected %t, got %t", tc.expectedOutput, result)
+				t.Fail()
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package conditions
+
+import (
+	"fmt"
+
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// SprintCondition returns a human-readable representation of the condition
+// with its type, status, reason, severity and message, e.g.
+//
+//    Ready: Status=False, Reason=NodesNotReady, Severity=Warning, Message="0 of 3 nodes are ready"
+//
+// Reason, severity and message are omitted when they are not set.
+func SprintCondition(condition capi.Condition) string {
+	return fmt.Sprintf("%s: %s", condition.Type, sprintConditionState(&condition))
+}
@@ -0,0 +1,44 @@
+package conditions
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestSprintCondition(t *testing.T) {
+	testCases := []struct {
+		name           string
+		condition      capi.Condition
+		expectedOutput string
+	}{
+		{
+			name:           "case 0: Condition with only status set",
+			condition:      capi.Condition{Type: capi.ReadyCondition, Status: corev1.ConditionTrue},
+			expectedOutput: "Ready: Status=True",
+		},
+		{
+			name: "case 1: Condition with reason, severity and message set",
+			condition: capi.Condition{
+				Type:     capi.ReadyCondition,
+				Status:   corev1.ConditionFalse,
+				Reason:   "NodesNotReady",
+				Severity: capi.ConditionSeverityWarning,
+				Message:  "0 of 3 nodes are ready",
+			},
+			expectedOutput: `Ready: Status=False, Reason=NodesNotReady, Severity=Warning, Message="0 of 3 nodes are ready"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			output := SprintCondition(tc.condition)
+			if output != tc.expectedOutput {
+				t.Fatalf("expected %q, got %q", tc.expectedOutput, output)
+			}
+		})
+	}
+}
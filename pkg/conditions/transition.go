@@ -0,0 +1,51 @@
+package conditions
+
+import (
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// transitionObservingObject wraps an Object and calls the observe function
+// with condition transitions, i.e. with conditions that were added or whose
+// status or reason has changed, every time conditions are set with
+// SetConditions. Since all functions from this package and from Cluster API
+// conditions package set conditions with SetConditions, this is how
+// wrappers like EventRecordingObject and HistoryRecordingObject intercept
+// condition changes.
+type transitionObservingObject struct {
+	Object
+
+	observe func(transitions ConditionChanges)
+	last    capi.Conditions
+}
+
+func newTransitionObservingObject(object Object, observe func(transitions ConditionChanges)) transitionObservingObject {
+	return transitionObservingObject{
+		Object:  object,
+		observe: observe,
+		last:    object.GetConditions().DeepCopy(),
+	}
+}
+
+// SetConditions sets conditions on the wrapped object and observes all
+// conditions whose status or reason has changed.
+func (o *transitionObservingObject) SetConditions(conditions capi.Conditions) {
+	// Conditions that were set last time are compared to the new ones,
+	// because capiconditions.Set changes the existing slice in place before
+	// calling SetConditions.
+	changes := diffConditions(o.last, conditions, WithEquivalence())
+
+	o.Object.SetConditions(conditions)
+	o.last = conditions.DeepCopy()
+
+	var transitions ConditionChanges
+	for _, change := range changes {
+		switch change.Kind {
+		case ChangeKindAdded, ChangeKindStatus, ChangeKindReason:
+			transitions = append(transitions, change)
+		}
+	}
+
+	if len(transitions) > 0 {
+		o.observe(transitions)
+	}
+}
@@ -0,0 +1,163 @@
+package conditions
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// UnstructuredObject wraps an unstructured object and implements Object
+// over its status.conditions field, so that all functions from this package
+// can be used with objects of any kind that have conditions in the same
+// format as Cluster API objects, e.g. provider CRs that are available only
+// as unstructured objects. Typed objects of other CRDs can be converted with
+// runtime.DefaultUnstructuredConverter.
+//
+// As opposed to capiconditions.UnstructuredGetter, reading conditions is
+// tolerant to malformed conditions:
+//
+//   - Conditions without type are ignored.
+//   - Conditions without status are handled as if they had status Unknown.
+//   - LastTransitionTime that cannot be parsed is handled as if it was not
+//     set.
+//
+// Setting conditions keeps unknown fields of existing conditions (e.g.
+// observedGeneration), as well as ignored malformed conditions.
+//
+// Example:
+//
+//    object := conditions.NewUnstructuredObject(azureCluster)
+//    if conditions.IsReadyFalse(object, conditions.WithSeverityError()) {
+//        // ...
+//    }
+//
+type UnstructuredObject struct {
+	*unstructured.Unstructured
+}
+
+// NewUnstructuredObject returns a new UnstructuredObject that wraps the
+// specified unstructured object. Conditions are read from and written to
+// the wrapped object.
+func NewUnstructuredObject(u *unstructured.Unstructured) *UnstructuredObject {
+	return &UnstructuredObject{
+		Unstructured: u,
+	}
+}
+
+// GetConditions returns conditions from status.conditions field. It returns
+// nil when the field is not set or it is not a list.
+func (o *UnstructuredObject) GetConditions() capi.Conditions {
+	items, ok, err := unstructured.NestedSlice(o.Object, "status", "conditions")
+	if err != nil || !ok {
+		return nil
+	}
+
+	conditions := capi.Conditions{}
+	for _, item := range items {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		condition, ok := conditionFromUnstructured(fields)
+		if !ok {
+			continue
+		}
+		conditions = append(conditions, condition)
+	}
+
+	return conditions
+}
+
+// SetConditions sets status.conditions field to the specified conditions.
+func (o *UnstructuredObject) SetConditions(conditions capi.Conditions) {
+	existingItems, _, _ := unstructured.NestedSlice(o.Object, "status", "conditions")
+
+	existingFields := map[capi.ConditionType]map[string]interface{}{}
+	var malformedItems []interface{}
+	for _, item := range existingItems {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			malformedItems = append(malformedItems, item)
+			continue
+		}
+
+		conditionType, _ := fields["type"].(string)
+		if conditionType == "" {
+			malformedItems = append(malformedItems, item)
+			continue
+		}
+		existingFields[capi.ConditionType(conditionType)] = fields
+	}
+
+	items := make([]interface{}, 0, len(conditions)+len(malformedItems))
+	for _, condition := range conditions {
+		fields := map[string]interface{}{}
+		for key, value := range existingFields[condition.Type] {
+			fields[key] = value
+		}
+		conditionToUnstructured(condition, fields)
+		items = append(items, fields)
+	}
+	items = append(items, malformedItems...)
+
+	// SetNestedSlice fails only when status field is not an object, in
+	// which case the object does not have conditions in supported format.
+	_ = unstructured.SetNestedSlice(o.Object, items, "status", "conditions")
+}
+
+func conditionFromUnstructured(fields map[string]interface{}) (capi.Condition, bool) {
+	conditionType, _ := fields["type"].(string)
+	if conditionType == "" {
+		return capi.Condition{}, false
+	}
+
+	condition := capi.Condition{
+		Type:   capi.ConditionType(conditionType),
+		Status: corev1.ConditionUnknown,
+	}
+	if status, _ := fields["status"].(string); status != "" {
+		condition.Status = corev1.ConditionStatus(status)
+	}
+	if severity, ok := fields["severity"].(string); ok {
+		condition.Severity = capi.ConditionSeverity(severity)
+	}
+	if reason, ok := fields["reason"].(string); ok {
+		condition.Reason = reason
+	}
+	if message, ok := fields["message"].(string); ok {
+		condition.Message = message
+	}
+	if lastTransitionTime, ok := fields["lastTransitionTime"].(string); ok {
+		t, err := time.Parse(time.RFC3339, lastTransitionTime)
+		if err == nil {
+			condition.LastTransitionTime = metav1.NewTime(t)
+		}
+	}
+
+	return condition, true
+}
+
+func conditionToUnstructured(condition capi.Condition, fields map[string]interface{}) {
+	fields["type"] = string(condition.Type)
+	fields["status"] = string(condition.Status)
+
+	setOrDelete := func(key string, value string) {
+		if value == "" {
+			delete(fields, key)
+		} else {
+			fields[key] = value
+		}
+	}
+	setOrDelete("severity", string(condition.Severity))
+	setOrDelete("reason", condition.Reason)
+	setOrDelete("message", condition.Message)
+	if condition.LastTransitionTime.IsZero() {
+		delete(fields, "lastTransitionTime")
+	} else {
+		fields["lastTransitionTime"] = condition.LastTransitionTime.UTC().Format(time.RFC3339)
+	}
+}
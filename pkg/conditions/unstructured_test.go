@@ -0,0 +1,150 @@
+package conditions
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func unstructuredWith(status interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+			"kind":       "AzureCluster",
+			"metadata": map[string]interface{}{
+				"namespace": "org-test",
+				"name":      "test",
+			},
+		},
+	}
+	if status != nil {
+		u.Object["status"] = status
+	}
+
+	return u
+}
+
+func TestUnstructuredObjectGetConditions(t *testing.T) {
+	testCases := []struct {
+		name               string
+		object             *unstructured.Unstructured
+		expectedConditions []string
+	}{
+		{
+			name:   "case 0: Object without status has no conditions",
+			object: unstructuredWith(nil),
+		},
+		{
+			name:   "case 1: Object with conditions that are not a list has no conditions",
+			object: unstructuredWith(map[string]interface{}{"conditions": "Ready"}),
+		},
+		{
+			name: "case 2: All condition fields are read",
+			object: unstructuredWith(map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{
+						"type":               "Ready",
+						"status":             "False",
+						"severity":           "Warning",
+						"reason":             "NotReady",
+						"message":            "Not ready yet",
+						"lastTransitionTime": "2021-01-01T12:00:00Z",
+					},
+				},
+			}),
+			expectedConditions: []string{
+				`Ready: Status=False, Reason=NotReady, Severity=Warning, Message="Not ready yet", LastTransitionTime=2021-01-01T12:00:00Z`,
+			},
+		},
+		{
+			name: "case 3: Malformed conditions are tolerated",
+			object: unstructuredWith(map[string]interface{}{
+				"conditions": []interface{}{
+					"Ready",
+					map[string]interface{}{"status": "True"},
+					map[string]interface{}{"type": "Creating"},
+					map[string]interface{}{"type": "Upgrading", "status": "True", "lastTransitionTime": "yesterday", "observedGeneration": int64(3)},
+				},
+			}),
+			expectedConditions: []string{
+				"Creating: Status=Unknown",
+				"Upgrading: Status=True",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			conditions := NewUnstructuredObject(tc.object).GetConditions()
+			if len(conditions) != len(tc.expectedConditions) {
+				t.Fatalf("expected %d conditions, got %d", len(tc.expectedConditions), len(conditions))
+			}
+
+			for i := range conditions {
+				text := sprintUnstructuredCondition(conditions[i])
+				if text != tc.expectedConditions[i] {
+					t.Logf("expected %s, got %s", tc.expectedConditions[i], text)
+					t.Fail()
+				}
+			}
+		})
+	}
+}
+
+func TestUnstructuredObjectSetConditions(t *testing.T) {
+	u := unstructuredWith(map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "False", "reason": "NotReady", "observedGeneration": int64(3)},
+			map[string]interface{}{"status": "True"},
+		},
+	})
+	object := NewUnstructuredObject(u)
+
+	capiconditions.MarkTrue(object, capi.ReadyCondition)
+	err := MarkCreatingTrue(object)
+	if err != nil {
+		t.Fatalf("expected no error, got %#v", err)
+	}
+
+	if !IsReadyTrue(object) || !IsCreatingTrue(object) {
+		t.Fatalf("expected Ready and Creating conditions with status True, got %v", object.GetConditions())
+	}
+
+	items, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	if len(items) != 3 {
+		t.Fatalf("expected 3 conditions, got %v", items)
+	}
+
+	ready := items[0].(map[string]interface{})
+	if ready["type"] != "Ready" || ready["status"] != "True" || ready["observedGeneration"] != int64(3) {
+		t.Fatalf("expected Ready condition with kept observedGeneration, got %v", ready)
+	}
+	if _, ok := ready["reason"]; ok {
+		t.Fatalf("expected reason to be removed, got %v", ready)
+	}
+	if _, ok := ready["lastTransitionTime"].(string); !ok {
+		t.Fatalf("expected lastTransitionTime to be set, got %v", ready)
+	}
+
+	malformed := items[2].(map[string]interface{})
+	if _, ok := malformed["type"]; ok {
+		t.Fatalf("expected malformed condition to be kept as the last one, got %v", items)
+	}
+}
+
+func sprintUnstructuredCondition(condition capi.Condition) string {
+	text := fmt.Sprintf("%s: %s", condition.Type, sprintConditionState(&condition))
+	if !condition.LastTransitionTime.IsZero() {
+		text += ", LastTransitionTime=" + condition.LastTransitionTime.UTC().Format(time.RFC3339)
+	}
+
+	return text
+}
+
+var _ Object = &UnstructuredObject{}
@@ -0,0 +1,242 @@
+package conditions
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	"k8s.io/apimachinery/pkg/types"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MessageDebouncer tracks when conditions were last written by
+// ConditionUpdater, so that changes of only condition message are written at
+// most once per debounce window. Since objects are fetched again in every
+// reconciliation loop, a single MessageDebouncer should be created in
+// controller setup and shared by all ConditionUpdaters. MessageDebouncer is
+// safe for concurrent use.
+type MessageDebouncer struct {
+	window time.Duration
+	clock  Clock
+
+	mutex   sync.Mutex
+	written map[conditionKey]time.Time
+}
+
+// NewMessageDebouncer returns a new MessageDebouncer with the specified
+// debounce window. Current time is taken from the clock set with WithClock.
+func NewMessageDebouncer(window time.Duration, options ...TimeOption) *MessageDebouncer {
+	return &MessageDebouncer{
+		window:  window,
+		clock:   clockOf(options),
+		written: map[conditionKey]time.Time{},
+	}
+}
+
+// Forget removes all write times for the specified object, e.g. when the
+// object is deleted.
+func (d *MessageDebouncer) Forget(object Object) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	key := conditionKeyOf(object, "")
+	for k := range d.written {
+		if k.kind == key.kind && k.namespace == key.namespace && k.name == key.name {
+			delete(d.written, k)
+		}
+	}
+}
+
+func (d *MessageDebouncer) isDebounced(key conditionKey) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	written, ok := d.written[key]
+	return ok && d.clock.Now().Sub(written) < d.window
+}
+
+func (d *MessageDebouncer) recordWrite(keys []conditionKey) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	now := d.clock.Now()
+	for _, key := range keys {
+		d.written[key] = now
+	}
+}
+
+type conditionUpdaterOptions struct {
+	debouncer *MessageDebouncer
+}
+
+// ConditionUpdaterOption is an option for NewConditionUpdater.
+type ConditionUpdaterOption func(options *conditionUpdaterOptions)
+
+// WithMessageDebouncer returns a ConditionUpdaterOption that sets the
+// MessageDebouncer, so that changes of only condition message are written
+// at most once per debounce window. Until the window passes, the previously
+// written message is kept. By default, message changes are not debounced.
+func WithMessageDebouncer(debouncer *MessageDebouncer) ConditionUpdaterOption {
+	return func(options *conditionUpdaterOptions) {
+		options.debouncer = debouncer
+	}
+}
+
+// ConditionUpdater wraps an Object and buffers all changes of its
+// conditions, i.e. changes made with any function from this package or from
+// Cluster API conditions package that sets conditions, so that they can be
+// written with a single status patch by Update. The patch is sent only when
+// conditions have changed since the object was wrapped, when compared with
+// AreEqual, so reconciliation loops that set the same conditions every time
+// do not write anything. The patch is a JSON merge patch that contains
+// status.conditions and the object resourceVersion for optimistic locking.
+// Since a merge patch replaces the whole conditions list, the patch is
+// rejected with a conflict error when the object was changed by another
+// writer after it was fetched, so that conditions set concurrently, e.g. by
+// another controller, are not overwritten.
+//
+// Example:
+//
+//    // In controller setup.
+//    r.debouncer = conditions.NewMessageDebouncer(time.Minute)
+//
+//    // In reconciliation loop.
+//    object := conditions.NewConditionUpdater(cluster, conditions.WithMessageDebouncer(r.debouncer))
+//    conditions.UpdateReady(object, conditions.WithSummaryStepCounter())
+//    err := conditions.MarkCreationCompleted(object)
+//    // ...
+//    written, err := object.Update(ctx, r.client)
+//
+type ConditionUpdater struct {
+	Object
+
+	options  conditionUpdaterOptions
+	original capi.Conditions
+}
+
+// NewConditionUpdater returns a new ConditionUpdater that wraps the
+// specified object. Current object conditions are considered to be already
+// written.
+func NewConditionUpdater(object Object, options ...ConditionUpdaterOption) *ConditionUpdater {
+	updaterOpts := conditionUpdaterOptions{}
+	for _, option := range options {
+		option(&updaterOpts)
+	}
+
+	return &ConditionUpdater{
+		Object:   object,
+		options:  updaterOpts,
+		original: object.GetConditions().DeepCopy(),
+	}
+}
+
+// Changes returns condition changes that would be written by Update, i.e.
+// all changes since the object was wrapped or last written, except
+// debounced message changes.
+func (u *ConditionUpdater) Changes() ConditionChanges {
+	return diffConditions(u.original, u.conditionsToWrite())
+}
+
+// Patch returns the JSON merge patch with status.conditions that would be
+// written by Update, and true. When the wrapped object has resourceVersion
+// set, it is included in the patch, so that the API server rejects the patch
+// with a conflict error when the object was changed in the meantime. When
+// there is nothing to write, it returns nil and false.
+func (u *ConditionUpdater) Patch() (client.Patch, bool, error) {
+	conditions := u.conditionsToWrite()
+	if len(diffConditions(u.original, conditions)) == 0 {
+		return nil, false, nil
+	}
+
+	if conditions == nil {
+		// Merge patch removes the field when it is set to null.
+		conditions = capi.Conditions{}
+	}
+
+	content := map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": conditions,
+		},
+	}
+	if resourceVersion := u.Object.GetResourceVersion(); resourceVersion != "" {
+		content["metadata"] = map[string]interface{}{
+			"resourceVersion": resourceVersion,
+		}
+	}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, false, microerror.Mask(err)
+	}
+
+	return client.RawPatch(types.MergePatchType, data), true, nil
+}
+
+// Update patches status of the wrapped object with changed conditions and
+// returns true. When there is nothing to write, it does not send any request
+// and returns false. After a successful update, the wrapped object contains
+// the object returned by the API server, and its conditions are considered
+// to be already written. The wrapped object must be of a type that is
+// registered in the client scheme.
+//
+// When the object was changed by another writer since it was fetched, the
+// API server returns a conflict error, which can be checked with
+// apierrors.IsConflict(microerror.Cause(err)). Nothing is written in that
+// case, so the object should be fetched again and its conditions computed
+// again, e.g. by requeueing the reconciliation.
+func (u *ConditionUpdater) Update(ctx context.Context, c client.Client) (bool, error) {
+	changes := u.Changes()
+	patch, ok, err := u.Patch()
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+	if !ok {
+		return false, nil
+	}
+
+	err = c.Status().Patch(ctx, u.Object, patch)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	if u.options.debouncer != nil {
+		var keys []conditionKey
+		for _, change := range changes {
+			keys = append(keys, conditionKeyOf(u.Object, change.Type))
+		}
+		u.options.debouncer.recordWrite(keys)
+	}
+	u.original = u.Object.GetConditions().DeepCopy()
+
+	return true, nil
+}
+
+// conditionsToWrite returns current object conditions, where conditions
+// whose message change is debounced are replaced with the original ones.
+func (u *ConditionUpdater) conditionsToWrite() capi.Conditions {
+	conditions := u.Object.GetConditions().DeepCopy()
+	for i := range conditions {
+		var original *capi.Condition
+		for j := range u.original {
+			if u.original[j].Type == conditions[i].Type {
+				original = &u.original[j]
+				break
+			}
+		}
+		if original == nil || AreEqual(original, &conditions[i]) {
+			continue
+		}
+
+		// Cluster API setters also change LastTransitionTime when only
+		// message changes, so it is reverted together with the message.
+		messageOnly := changeKind(original, &conditions[i]) == ChangeKindMessage
+		if messageOnly && u.options.debouncer != nil && u.options.debouncer.isDebounced(conditionKeyOf(u.Object, conditions[i].Type)) {
+			conditions[i] = *original
+		}
+	}
+
+	return conditions
+}
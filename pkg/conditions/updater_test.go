@@ -0,0 +1,286 @@
+package conditions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// countingClient counts status patches.
+type countingClient struct {
+	client.Client
+	patches []client.Patch
+}
+
+func (c *countingClient) Status() client.StatusWriter {
+	return &countingStatusWriter{StatusWriter: c.Client.Status(), client: c}
+}
+
+type countingStatusWriter struct {
+	client.StatusWriter
+	client *countingClient
+}
+
+func (w *countingStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	w.client.patches = append(w.client.patches, patch)
+	return w.StatusWriter.Patch(ctx, obj, patch, opts...)
+}
+
+func newUpdaterTestClient(t *testing.T, objects ...client.Object) *countingClient {
+	scheme := runtime.NewScheme()
+	err := capi.AddToScheme(scheme)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	return &countingClient{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()}
+}
+
+func newUpdaterTestCluster() *capi.Cluster {
+	return &capi.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "org-test", Name: "test"},
+		Spec:       capi.ClusterSpec{Paused: true},
+	}
+}
+
+func TestConditionUpdaterReducesWrites(t *testing.T) {
+	ctx := context.Background()
+	clock := NewFakeClock(time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC))
+	const reconciliations = 30
+
+	// reconcile sets conditions in the same way as a controller would do in
+	// every reconciliation loop: Ready only changes once, while message of
+	// NodePoolsReady changes every time.
+	reconcile := func(object Object, i int) {
+		if i < reconciliations/2 {
+			capiconditions.MarkFalse(object, capi.ReadyCondition, "Creating", capi.ConditionSeverityInfo, "")
+		} else {
+			capiconditions.MarkTrue(object, capi.ReadyCondition)
+		}
+		capiconditions.MarkFalse(object, NodePoolsReady, NodePoolsNotReadyReason, capi.ConditionSeverityInfo, "%d seconds since creation", i*10)
+		capiconditions.MarkTrue(object, InfrastructureReady)
+	}
+
+	testCases := []struct {
+		name           string
+		update         func(c *countingClient, cluster *capi.Cluster, debouncer *MessageDebouncer, i int)
+		expectedWrites int
+	}{
+		{
+			name: "case 0: Without ConditionUpdater every reconciliation writes status",
+			update: func(c *countingClient, cluster *capi.Cluster, _ *MessageDebouncer, i int) {
+				reconcile(cluster, i)
+				data, _ := json.Marshal(map[string]interface{}{"status": map[string]interface{}{"conditions": cluster.Status.Conditions}})
+				err := c.Status().Patch(ctx, cluster, client.RawPatch("application/merge-patch+json", data))
+				if err != nil {
+					t.Fatalf("error == %#v, want nil", err)
+				}
+			},
+			expectedWrites: reconciliations,
+		},
+		{
+			name: "case 1: ConditionUpdater without debouncer writes only changes",
+			update: func(c *countingClient, cluster *capi.Cluster, _ *MessageDebouncer, i int) {
+				object := NewConditionUpdater(cluster)
+				reconcile(object, i)
+				_, err := object.Update(ctx, c)
+				if err != nil {
+					t.Fatalf("error == %#v, want nil", err)
+				}
+			},
+			expectedWrites: reconciliations,
+		},
+		{
+			name: "case 2: ConditionUpdater with debouncer writes message changes once per window",
+			update: func(c *countingClient, cluster *capi.Cluster, debouncer *MessageDebouncer, i int) {
+				object := NewConditionUpdater(cluster, WithMessageDebouncer(debouncer))
+				reconcile(object, i)
+				_, err := object.Update(ctx, c)
+				if err != nil {
+					t.Fatalf("error == %#v, want nil", err)
+				}
+			},
+			// Reconciliations happen every 10 seconds for 5 minutes, so
+			// there is the first write, one message write per minute
+			// window, and the write of Ready status change.
+			expectedWrites: 1 + 4 + 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+			clock.Set(time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC))
+			c := newUpdaterTestClient(t, newUpdaterTestCluster())
+			debouncer := NewMessageDebouncer(time.Minute, WithClock(clock))
+
+			for i := 0; i < reconciliations; i++ {
+				cluster := &capi.Cluster{}
+				err := c.Get(ctx, client.ObjectKey{Namespace: "org-test", Name: "test"}, cluster)
+				if err != nil {
+					t.Fatalf("error == %#v, want nil", err)
+				}
+
+				tc.update(c, cluster, debouncer, i)
+				clock.Step(10 * time.Second)
+			}
+
+			if len(c.patches) != tc.expectedWrites {
+				t.Fatalf("expected %d writes, got %d", tc.expectedWrites, len(c.patches))
+			}
+
+			cluster := &capi.Cluster{}
+			err := c.Get(ctx, client.ObjectKey{Namespace: "org-test", Name: "test"}, cluster)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+			if !IsReadyTrue(cluster) || !IsInfrastructureReadyTrue(cluster) || !IsNodePoolsReadyFalse(cluster) {
+				t.Fatalf("expected all condition changes to be written, got %v", cluster.Status.Conditions)
+			}
+		})
+	}
+}
+
+func TestConditionUpdaterSkipsNoOps(t *testing.T) {
+	ctx := context.Background()
+	cluster := newUpdaterTestCluster()
+	capiconditions.MarkTrue(cluster, capi.ReadyCondition)
+	capiconditions.MarkFalse(cluster, Creating, CreationCompletedReason, capi.ConditionSeverityNone, "")
+	c := newUpdaterTestClient(t, cluster)
+
+	for i := 0; i < 10; i++ {
+		object := NewConditionUpdater(cluster)
+		capiconditions.MarkTrue(object, capi.ReadyCondition)
+		capiconditions.MarkFalse(object, Creating, CreationCompletedReason, capi.ConditionSeverityNone, "")
+
+		written, err := object.Update(ctx, c)
+		if err != nil {
+			t.Fatalf("error == %#v, want nil", err)
+		}
+		if written || len(object.Changes()) != 0 {
+			t.Fatalf("expected nothing to be written, got changes %v", object.Changes())
+		}
+	}
+
+	if len(c.patches) != 0 {
+		t.Fatalf("expected no writes, got %d", len(c.patches))
+	}
+}
+
+func TestConditionUpdaterPatch(t *testing.T) {
+	cluster := newUpdaterTestCluster()
+	cluster.Status.Phase = "Provisioned"
+	capiconditions.MarkTrue(cluster, capi.ReadyCondition)
+
+	object := NewConditionUpdater(cluster)
+	capiconditions.MarkFalse(object, capi.ReadyCondition, "NodesNotReady", capi.ConditionSeverityWarning, "0 of 3 nodes are ready")
+
+	patch, ok, err := object.Patch()
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	if !ok {
+		t.Fatalf("expected patch")
+	}
+
+	data, err := patch.Data(cluster)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	var content map[string]map[string][]map[string]interface{}
+	err = json.Unmarshal(data, &content)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	if len(content) != 1 || len(content["status"]) != 1 || len(content["status"]["conditions"]) != 1 {
+		t.Fatalf("expected patch with only status.conditions, got %s", data)
+	}
+	if content["status"]["conditions"][0]["reason"] != "NodesNotReady" || fmt.Sprint(patch.Type()) != "application/merge-patch+json" {
+		t.Fatalf("expected merge patch with changed Ready condition, got %s %s", patch.Type(), data)
+	}
+
+	// Status change is not debounced.
+	debouncer := NewMessageDebouncer(time.Hour)
+	debouncer.recordWrite([]conditionKey{conditionKeyOf(cluster, capi.ReadyCondition)})
+	cluster = newUpdaterTestCluster()
+	capiconditions.MarkTrue(cluster, capi.ReadyCondition)
+	object = NewConditionUpdater(cluster, WithMessageDebouncer(debouncer))
+	capiconditions.MarkFalse(object, capi.ReadyCondition, "NodesNotReady", capi.ConditionSeverityWarning, "")
+	if _, ok, _ := object.Patch(); !ok {
+		t.Fatalf("expected status change not to be debounced")
+	}
+}
+
+func TestConditionUpdaterConflict(t *testing.T) {
+	ctx := context.Background()
+	c := newUpdaterTestClient(t, newUpdaterTestCluster())
+
+	cluster := &capi.Cluster{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: "org-test", Name: "test"}, cluster)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	object := NewConditionUpdater(cluster)
+	capiconditions.MarkFalse(object, capi.ReadyCondition, "NodesNotReady", capi.ConditionSeverityWarning, "")
+
+	patch, _, err := object.Patch()
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	data, err := patch.Data(cluster)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	var content struct {
+		Metadata metav1.ObjectMeta `json:"metadata"`
+	}
+	err = json.Unmarshal(data, &content)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	if content.Metadata.ResourceVersion == "" || content.Metadata.ResourceVersion != cluster.ResourceVersion {
+		t.Fatalf("expected patch with resourceVersion %q, got %s", cluster.ResourceVersion, data)
+	}
+
+	// Another writer changes a different condition between Get and Update.
+	other := &capi.Cluster{}
+	err = c.Get(ctx, client.ObjectKey{Namespace: "org-test", Name: "test"}, other)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	otherObject := NewConditionUpdater(other)
+	capiconditions.MarkTrue(otherObject, InfrastructureReady)
+	_, err = otherObject.Update(ctx, c)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	written, err := object.Update(ctx, c)
+	if !apierrors.IsConflict(microerror.Cause(err)) {
+		t.Fatalf("error == %#v, want conflict", err)
+	}
+	if written || len(object.Changes()) == 0 {
+		t.Fatalf("expected nothing to be written and changes to be kept, got changes %v", object.Changes())
+	}
+
+	stored := &capi.Cluster{}
+	err = c.Get(ctx, client.ObjectKey{Namespace: "org-test", Name: "test"}, stored)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	if !IsInfrastructureReadyTrue(stored) || capiconditions.Has(stored, capi.ReadyCondition) {
+		t.Fatalf("expected concurrent change to be kept, got %v", stored.Status.Conditions)
+	}
+}
@@ -1,6 +1,7 @@
 package conditions
 
 import (
+	"github.com/giantswarm/microerror"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
 	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
 )
@@ -29,23 +30,32 @@ const (
 	UpgradePendingReason = "UpgradePending"
 )
 
+// upgradingDescriptor describes Upgrading condition.
+var upgradingDescriptor = ConditionDescriptor{
+	Type: Upgrading,
+	Reasons: map[string]capi.ConditionSeverity{
+		UpgradeCompletedReason:  capi.ConditionSeverityInfo,
+		UpgradeNotStartedReason: capi.ConditionSeverityInfo,
+		UpgradePendingReason:    capi.ConditionSeverityInfo,
+	},
+}
+
+// UpgradingDescriptor returns a copy of the descriptor of Upgrading condition.
+func UpgradingDescriptor() ConditionDescriptor {
+	return upgradingDescriptor.deepCopy()
+}
+
 // GetUpgrading tries to get Upgrading condition from the specified object. If
 // the Upgrading condition was found, it returns a copy of the condition and
 // true, otherwise it returns an empty struct and false.
 func GetUpgrading(object Object) (capi.Condition, bool) {
-	c := capiconditions.Get(object, Upgrading)
-
-	if c != nil {
-		return *c, true
-	} else {
-		return capi.Condition{}, false
-	}
+	return upgradingDescriptor.Get(object)
 }
 
 // IsUpgradingTrue checks if specified object is in Upgrading condition (if
 // Upgrading condition is set with status True).
 func IsUpgradingTrue(object Object) bool {
-	return capiconditions.IsTrue(object, Upgrading)
+	return upgradingDescriptor.IsTrue(object)
 }
 
 // IsUpgradingFalse checks if specified object is not in Upgrading condition (if
@@ -59,27 +69,14 @@ func IsUpgradingTrue(object Object) bool {
 //    IsUpgradingFalse(cluster, WithUpgradeNotStartedReason())
 //
 func IsUpgradingFalse(object Object, checkOptions ...CheckOption) bool {
-	upgrading := capiconditions.Get(object, Upgrading)
-	if !IsFalse(upgrading) {
-		// Upgrading condition is not set or it does not have status False
-		return false
-	}
-
-	for _, checkOption := range checkOptions {
-		if !checkOption(upgrading) {
-			// additional check has failed
-			return false
-		}
-	}
-
-	return true
+	return upgradingDescriptor.IsFalse(object, checkOptions...)
 }
 
 // IsUpgradingUnknown checks if it is unknown whether the specified object is in
 // Upgrading condition or not (if Upgrading condition is not set, or it is set
 // with status Unknown).
 func IsUpgradingUnknown(object Object) bool {
-	return capiconditions.IsUnknown(object, Upgrading)
+	return upgradingDescriptor.IsUnknown(object)
 }
 
 // WithUpgradeCompletedReason returns a CheckOption that checks if condition
@@ -93,3 +90,91 @@ func WithUpgradeCompletedReason() CheckOption {
 func WithUpgradeNotStartedReason() CheckOption {
 	return WithReason(UpgradeNotStartedReason)
 }
+
+// WithUpgradePendingReason returns a CheckOption that checks if condition
+// reason is set to UpgradePending.
+func WithUpgradePendingReason() CheckOption {
+	return WithReason(UpgradePendingReason)
+}
+
+// MarkUpgradeNotStarted sets Upgrading condition with status False, reason
+// UpgradeNotStarted and severity Info on the specified object. It is usually
+// set during or right after creation, so it returns
+// InvalidLifecycleTransitionError if the upgrade has already been pending,
+// started or completed. It can also be set on a new object without Creating
+// and Upgrading conditions, which stays in New lifecycle phase, so that its
+// creation can still be started with MarkCreatingTrue or it can be marked
+// with MarkExistingObject. If it is already set, the condition is not
+// changed. Current time is taken from the clock set with WithClock.
+func MarkUpgradeNotStarted(object Object, options ...TimeOption) error {
+	condition := upgradingDescriptor.falseCondition(
+		UpgradeNotStartedReason,
+		"Upgrade has not been started")
+
+	if IsUpgradingFalse(object, WithUpgradeNotStartedReason()) {
+		// Already set, nothing to do here.
+		return nil
+	}
+
+	err := setLifecycleCondition(object, condition, options...)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// MarkUpgradePending sets Upgrading condition with status False, reason
+// UpgradePending and severity Info on the specified object. An upgrade can be
+// pending only after the creation has been completed and while the object is
+// not already being upgraded, otherwise InvalidLifecycleTransitionError is
+// returned. Current time is taken from the clock set with WithClock.
+func MarkUpgradePending(object Object, options ...TimeOption) error {
+	condition := upgradingDescriptor.falseCondition(
+		UpgradePendingReason,
+		"Upgrade is pending")
+
+	err := setLifecycleCondition(object, condition, options...)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// MarkUpgradingTrue sets Upgrading condition with status True on the
+// specified object. An upgrade can be started only after the creation has
+// been completed, otherwise InvalidLifecycleTransitionError is returned.
+// Current time is taken from the clock set with WithClock.
+func MarkUpgradingTrue(object Object, options ...TimeOption) error {
+	err := setLifecycleCondition(object, capiconditions.TrueCondition(Upgrading), options...)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// MarkUpgradeCompleted sets Upgrading condition with status False, reason
+// UpgradeCompleted and severity Info on the specified object. Upgrade can be
+// completed only while Upgrading condition has status True, otherwise
+// InvalidLifecycleTransitionError is returned. If the upgrade has already
+// been completed, the condition is not changed. Current time is taken from
+// the clock set with WithClock.
+func MarkUpgradeCompleted(object Object, options ...TimeOption) error {
+	condition := upgradingDescriptor.falseCondition(
+		UpgradeCompletedReason,
+		"Upgrade has been completed")
+
+	if IsUpgradingFalse(object, WithUpgradeCompletedReason()) {
+		// Upgrade has already been completed, nothing to do here.
+		return nil
+	}
+
+	err := setLifecycleCondition(object, condition, options...)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
 	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
 )
 
 func TestGetUpgrading(t *testing.T) {
@@ -292,3 +293,274 @@ func TestIsUpgradingUnknown(t *testing.T) {
 		})
 	}
 }
+
+func TestMarkUpgradeNotStarted(t *testing.T) {
+	testCases := []struct {
+		name         string
+		object       Object
+		errorMatcher func(error) bool
+	}{
+		{
+			name:   "case 0: Upgrading is set for CR without condition Upgrading",
+			object: clusterWith(Creating, corev1.ConditionTrue),
+		},
+		{
+			name:   "case 1: Upgrading is set for created CR with condition Upgrading with status Unknown",
+			object: clusterWithCreatingFalseAnd(corev1.ConditionUnknown, ""),
+		},
+		{
+			name:         "case 2: Error is returned for CR with condition Upgrading with status True",
+			object:       clusterWith(Upgrading, corev1.ConditionTrue),
+			errorMatcher: IsInvalidLifecycleTransition,
+		},
+		{
+			name:         "case 3: Error is returned for CR with condition Upgrading with status False",
+			object:       clusterWith(Upgrading, corev1.ConditionFalse),
+			errorMatcher: IsInvalidLifecycleTransition,
+		},
+		{
+			name:         "case 4: Error is returned for CR with condition Upgrading with unsupported status",
+			object:       machinePoolWith(Upgrading, "Sometimes"),
+			errorMatcher: IsUnsupportedConditionStatus,
+		},
+		{
+			name:   "case 5: Upgrading is set for CR without conditions",
+			object: machinePoolWithoutConditions(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			err := MarkUpgradeNotStarted(tc.object)
+
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Logf("expected matching error, got %#v", err)
+					t.Fail()
+				}
+				return
+			} else if err != nil {
+				t.Logf("expected no error, got %#v", err)
+				t.Fail()
+			}
+
+			if !IsUpgradingFalse(tc.object, WithUpgradeNotStartedReason(), WithSeverityInfo()) {
+				t.Logf("Upgrading was not set correctly, got %s", sprintConditionForObject(tc.object, Upgrading))
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestMarkUpgradePending(t *testing.T) {
+	testCases := []struct {
+		name         string
+		object       Object
+		errorMatcher func(error) bool
+	}{
+		{
+			name:   "case 0: Upgrading is set for created CR without condition Upgrading",
+			object: clusterWith(Creating, corev1.ConditionFalse),
+		},
+		{
+			name:   "case 1: Upgrading is set for created CR with condition Upgrading with status False",
+			object: clusterWithCreatingFalseAnd(corev1.ConditionFalse, UpgradeCompletedReason),
+		},
+		{
+			name:         "case 2: Error is returned for CR with condition Creating with status True",
+			object:       clusterWith(Creating, corev1.ConditionTrue),
+			errorMatcher: IsInvalidLifecycleTransition,
+		},
+		{
+			name:         "case 3: Error is returned for CR without condition Creating",
+			object:       machinePoolWithoutConditions(),
+			errorMatcher: IsInvalidLifecycleTransition,
+		},
+		{
+			name:         "case 4: Error is returned for CR with condition Upgrading with status True",
+			object:       clusterWithCreatingFalseAnd(corev1.ConditionTrue, ""),
+			errorMatcher: IsInvalidLifecycleTransition,
+		},
+		{
+			name:         "case 5: Error is returned for CR with condition Upgrading with unsupported status",
+			object:       clusterWithCreatingFalseAnd("Whatever", ""),
+			errorMatcher: IsUnsupportedConditionStatus,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			err := MarkUpgradePending(tc.object)
+
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Logf("expected matching error, got %#v", err)
+					t.Fail()
+				}
+				return
+			} else if err != nil {
+				t.Logf("expected no error, got %#v", err)
+				t.Fail()
+			}
+
+			if !IsUpgradingFalse(tc.object, WithUpgradePendingReason(), WithSeverityInfo()) {
+				t.Logf("Upgrading was not set correctly, got %s", sprintConditionForObject(tc.object, Upgrading))
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestMarkUpgradingTrue(t *testing.T) {
+	testCases := []struct {
+		name         string
+		object       Object
+		errorMatcher func(error) bool
+	}{
+		{
+			name:   "case 0: Upgrading is set for created CR without condition Upgrading",
+			object: clusterWith(Creating, corev1.ConditionFalse),
+		},
+		{
+			name:   "case 1: Upgrading is set for created CR with pending upgrade",
+			object: clusterWithCreatingFalseAnd(corev1.ConditionFalse, UpgradePendingReason),
+		},
+		{
+			name:   "case 2: Upgrading stays True for CR with condition Upgrading with status True",
+			object: clusterWithCreatingFalseAnd(corev1.ConditionTrue, ""),
+		},
+		{
+			name:         "case 3: Error is returned for CR with condition Creating with status True",
+			object:       clusterWith(Creating, corev1.ConditionTrue),
+			errorMatcher: IsInvalidLifecycleTransition,
+		},
+		{
+			name:         "case 4: Error is returned for CR with condition Creating with unsupported status",
+			object:       clusterWith(Creating, "Perhaps"),
+			errorMatcher: IsUnsupportedConditionStatus,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			err := MarkUpgradingTrue(tc.object)
+
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Logf("expected matching error, got %#v", err)
+					t.Fail()
+				}
+				return
+			} else if err != nil {
+				t.Logf("expected no error, got %#v", err)
+				t.Fail()
+			}
+
+			if !IsUpgradingTrue(tc.object) {
+				t.Logf("Upgrading was not set correctly, got %s", sprintConditionForObject(tc.object, Upgrading))
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestMarkUpgradeCompleted(t *testing.T) {
+	testCases := []struct {
+		name         string
+		object       Object
+		errorMatcher func(error) bool
+	}{
+		{
+			name:   "case 0: Upgrade is completed for CR with condition Upgrading with status True",
+			object: clusterWithCreatingFalseAnd(corev1.ConditionTrue, ""),
+		},
+		{
+			name:   "case 1: Upgrading is not changed for CR with completed upgrade",
+			object: clusterWithCreatingFalseAnd(corev1.ConditionFalse, UpgradeCompletedReason),
+		},
+		{
+			name:         "case 2: Error is returned for CR with pending upgrade",
+			object:       clusterWithCreatingFalseAnd(corev1.ConditionFalse, UpgradePendingReason),
+			errorMatcher: IsInvalidLifecycleTransition,
+		},
+		{
+			name:         "case 3: Error is returned for CR without condition Upgrading",
+			object:       clusterWithoutConditions(),
+			errorMatcher: IsInvalidLifecycleTransition,
+		},
+		{
+			name:         "case 4: Error is returned for CR with condition Upgrading with unsupported status",
+			object:       machinePoolWith(Upgrading, "NotSure"),
+			errorMatcher: IsUnsupportedConditionStatus,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			err := MarkUpgradeCompleted(tc.object)
+
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Logf("expected matching error, got %#v", err)
+					t.Fail()
+				}
+				return
+			} else if err != nil {
+				t.Logf("expected no error, got %#v", err)
+				t.Fail()
+			}
+
+			if !IsUpgradingFalse(tc.object, WithUpgradeCompletedReason()) {
+				t.Logf("Upgrading was not set correctly, got %s", sprintConditionForObject(tc.object, Upgrading))
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestMarkUpgradeIsIdempotent(t *testing.T) {
+	testCases := []struct {
+		name   string
+		reason string
+		mark   func(Object, ...TimeOption) error
+	}{
+		{
+			name:   "case 0: MarkUpgradeNotStarted does not change condition with reason UpgradeNotStarted",
+			reason: UpgradeNotStartedReason,
+			mark:   MarkUpgradeNotStarted,
+		},
+		{
+			name:   "case 1: MarkUpgradeCompleted does not change condition with reason UpgradeCompleted",
+			reason: UpgradeCompletedReason,
+			mark:   MarkUpgradeCompleted,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			object := clusterWithCreatingFalseAnd(corev1.ConditionFalse, tc.reason)
+			capiconditions.MarkFalse(object, Upgrading, tc.reason, capi.ConditionSeverityInfo, "Set by another controller")
+			expected := *capiconditions.Get(object, Upgrading)
+
+			err := tc.mark(object)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			current := capiconditions.Get(object, Upgrading)
+			if !AreEqual(current, &expected) {
+				t.Fatalf("expected %s, got %s", sprintCondition(&expected), sprintCondition(current))
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package conditions
+
+import (
+	"context"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultWaitInterval is the default time between two consecutive
+	// object reads in WaitFor.
+	DefaultWaitInterval = 5 * time.Second
+)
+
+// WaitPredicate is satisfied when Check returns true for the condition of
+// type Type. Check is called with nil when the condition is not set, so all
+// check functions from this package can be used, e.g. IsTrue.
+type WaitPredicate struct {
+	Type  capi.ConditionType
+	Check CheckOption
+}
+
+// ConditionTrue returns a WaitPredicate that is satisfied when the
+// condition of the specified type has status True.
+func ConditionTrue(conditionType capi.ConditionType) WaitPredicate {
+	return WaitPredicate{
+		Type:  conditionType,
+		Check: IsTrue,
+	}
+}
+
+// ConditionFalse returns a WaitPredicate that is satisfied when the
+// condition of the specified type has status False and all optionally
+// specified checks are successful.
+func ConditionFalse(conditionType capi.ConditionType, checkOptions ...CheckOption) WaitPredicate {
+	return WaitPredicate{
+		Type: conditionType,
+		Check: func(condition *capi.Condition) bool {
+			return IsFalse(condition, checkOptions...)
+		},
+	}
+}
+
+type waitOptions struct {
+	timeout     time.Duration
+	interval    time.Duration
+	backoff     float64
+	maxInterval time.Duration
+	failFast    []WaitPredicate
+}
+
+// WaitOption is an option for WaitFor.
+type WaitOption func(options *waitOptions)
+
+// WithWaitTimeout returns a WaitOption that makes WaitFor give up after the
+// specified time. By default, WaitFor waits until the context is done.
+func WithWaitTimeout(timeout time.Duration) WaitOption {
+	return func(options *waitOptions) {
+		options.timeout = timeout
+	}
+}
+
+// WithWaitInterval returns a WaitOption that sets the time between two
+// consecutive object reads. Default is DefaultWaitInterval, which is also
+// used when the specified interval is not positive, so that WaitFor never
+// reads the object in a busy loop.
+func WithWaitInterval(interval time.Duration) WaitOption {
+	return func(options *waitOptions) {
+		if interval > 0 {
+			options.interval = interval
+		} else {
+			options.interval = DefaultWaitInterval
+		}
+	}
+}
+
+// WithWaitBackoff returns a WaitOption that multiplies the time between two
+// consecutive object reads by the specified factor after every read, up to
+// the specified maximum interval.
+func WithWaitBackoff(factor float64, maxInterval time.Duration) WaitOption {
+	return func(options *waitOptions) {
+		options.backoff = factor
+		options.maxInterval = maxInterval
+	}
+}
+
+// WithFailFast returns a WaitOption that makes WaitFor stop waiting and
+// return WaitFailedError as soon as any of the specified predicates is
+// satisfied, e.g. when Ready condition has severity Error and the awaited
+// state will not be reached without an intervention.
+func WithFailFast(predicates ...WaitPredicate) WaitOption {
+	return func(options *waitOptions) {
+		options.failFast = append(options.failFast, predicates...)
+	}
+}
+
+// WaitError is returned by WaitFor when the awaited predicate was not
+// satisfied. It contains the condition that was last observed, which is nil
+// when the object or the condition was not found. Use IsWaitTimeout and
+// IsWaitFailed to check why waiting has stopped.
+type WaitError struct {
+	// Key is the key of the awaited object.
+	Key client.ObjectKey
+
+	// Type is the type of the last observed condition, i.e. the type of the
+	// awaited condition or the type of the condition that satisfied a
+	// fail-fast predicate.
+	Type capi.ConditionType
+
+	// LastObserved is the last observed condition of type Type.
+	LastObserved *capi.Condition
+
+	err error
+}
+
+// Error returns the error message.
+func (e *WaitError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying WaitTimeoutError or WaitFailedError.
+func (e *WaitError) Unwrap() error {
+	return e.err
+}
+
+// WaitFor reads the object with the specified key into the specified object
+// until the specified predicate is satisfied. It returns nil when the
+// predicate is satisfied, and WaitError when the timeout is reached, the
+// context is done, or a fail-fast predicate is satisfied. Errors other than
+// NotFound returned by the client are returned immediately.
+//
+// Example:
+//
+//    cluster := &capi.Cluster{}
+//    err := conditions.WaitFor(ctx, ctrlClient, key, cluster,
+//        conditions.ConditionFalse(conditions.Upgrading, conditions.WithUpgradeCompletedReason()),
+//        conditions.WithWaitTimeout(30*time.Minute),
+//        conditions.WithFailFast(conditions.ConditionFalse(capi.ReadyCondition, conditions.WithSeverityError())))
+//
+func WaitFor(ctx context.Context, c client.Client, key client.ObjectKey, object Object, predicate WaitPredicate, options ...WaitOption) error {
+	waitOpts := waitOptions{
+		interval: DefaultWaitInterval,
+	}
+	for _, option := range options {
+		option(&waitOpts)
+	}
+
+	if waitOpts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, waitOpts.timeout)
+		defer cancel()
+	}
+
+	var lastObserved *capi.Condition
+	interval := waitOpts.interval
+	for {
+		err := c.Get(ctx, key, object)
+		if apierrors.IsNotFound(err) {
+			// Object is not created yet, keep waiting.
+			lastObserved = nil
+		} else if err != nil && ctx.Err() != nil {
+			// Client has failed because the context is done.
+		} else if err != nil {
+			return microerror.Mask(err)
+		} else {
+			lastObserved = capiconditions.Get(object, predicate.Type)
+			if predicate.Check(lastObserved) {
+				return nil
+			}
+
+			for _, failFast := range waitOpts.failFast {
+				condition := capiconditions.Get(object, failFast.Type)
+				if failFast.Check(condition) {
+					return &WaitError{
+						Key:          key,
+						Type:         failFast.Type,
+						LastObserved: condition,
+						err:          microerror.Maskf(WaitFailedError, "%s", WaitErrorMessage(key, failFast.Type, condition)),
+					}
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return &WaitError{
+				Key:          key,
+				Type:         predicate.Type,
+				LastObserved: lastObserved,
+				err:          microerror.Maskf(WaitTimeoutError, "%s", WaitErrorMessage(key, predicate.Type, lastObserved)),
+			}
+		case <-time.After(interval):
+		}
+
+		if waitOpts.backoff > 1 {
+			interval = time.Duration(float64(interval) * waitOpts.backoff)
+			if waitOpts.maxInterval > 0 && interval > waitOpts.maxInterval {
+				interval = waitOpts.maxInterval
+			}
+		}
+	}
+}
+
+func sprintObservedCondition(condition *capi.Condition) string {
+	if condition == nil {
+		return conditionNotSet
+	}
+
+	return sprintConditionState(condition)
+}
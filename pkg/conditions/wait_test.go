@@ -0,0 +1,186 @@
+package conditions
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// updatingClient changes the stored object before the specified read.
+type updatingClient struct {
+	client.Client
+
+	reads    int
+	updateAt int
+	update   func(cluster *capi.Cluster)
+}
+
+func (c *updatingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	c.reads++
+	if c.reads == c.updateAt {
+		cluster := &capi.Cluster{}
+		err := c.Client.Get(ctx, key, cluster)
+		if err != nil {
+			return err
+		}
+		c.update(cluster)
+		err = c.Client.Update(ctx, cluster)
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.Client.Get(ctx, key, obj)
+}
+
+type failingClient struct {
+	client.Client
+}
+
+func (c failingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	return errors.New("connection refused")
+}
+
+func TestWaitFor(t *testing.T) {
+	key := client.ObjectKey{Namespace: "org-test", Name: "test"}
+	testCases := []struct {
+		name            string
+		conditions      capi.Conditions
+		updateAt        int
+		update          func(cluster *capi.Cluster)
+		predicate       WaitPredicate
+		options         []WaitOption
+		errorMatcher    func(error) bool
+		expectedReads   int
+		expectedLastObs *corev1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name:          "case 0: Predicate that is already satisfied returns after first read",
+			conditions:    capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionTrue}},
+			predicate:     ConditionTrue(capi.ReadyCondition),
+			expectedReads: 1,
+		},
+		{
+			name:       "case 1: Predicate is satisfied after the object is updated",
+			conditions: capi.Conditions{{Type: Upgrading, Status: corev1.ConditionTrue}},
+			updateAt:   3,
+			update: func(cluster *capi.Cluster) {
+				cluster.Status.Conditions = capi.Conditions{{Type: Upgrading, Status: corev1.ConditionFalse, Reason: UpgradeCompletedReason}}
+			},
+			predicate:     ConditionFalse(Upgrading, WithUpgradeCompletedReason()),
+			expectedReads: 3,
+		},
+		{
+			name:            "case 2: Timeout returns error with last observed condition",
+			conditions:      capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionFalse, Severity: capi.ConditionSeverityInfo, Message: "50% of nodes are ready"}},
+			predicate:       ConditionTrue(capi.ReadyCondition),
+			options:         []WaitOption{WithWaitTimeout(50 * time.Millisecond)},
+			errorMatcher:    IsWaitTimeout,
+			expectedLastObs: statusPtr(corev1.ConditionFalse),
+			expectedMessage: `last observed condition Ready: Status=False, Severity=Info, Message="50% of nodes are ready"`,
+		},
+		{
+			name:       "case 3: Fail-fast predicate stops waiting",
+			conditions: capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionFalse, Severity: capi.ConditionSeverityInfo}},
+			updateAt:   2,
+			update: func(cluster *capi.Cluster) {
+				cluster.Status.Conditions[0].Severity = capi.ConditionSeverityError
+			},
+			predicate:       ConditionTrue(capi.ReadyCondition),
+			options:         []WaitOption{WithFailFast(ConditionFalse(capi.ReadyCondition, WithSeverityError()))},
+			errorMatcher:    IsWaitFailed,
+			expectedReads:   2,
+			expectedLastObs: statusPtr(corev1.ConditionFalse),
+		},
+		{
+			name:         "case 4: Timeout without condition returns error without last observed condition",
+			predicate:    ConditionTrue(capi.ReadyCondition),
+			options:      []WaitOption{WithWaitTimeout(50 * time.Millisecond), WithWaitBackoff(2, 20*time.Millisecond)},
+			errorMatcher: IsWaitTimeout,
+		},
+		{
+			name:          "case 5: Interval that is not positive is replaced with default interval",
+			predicate:     ConditionTrue(capi.ReadyCondition),
+			options:       []WaitOption{WithWaitInterval(0), WithWaitTimeout(50 * time.Millisecond)},
+			errorMatcher:  IsWaitTimeout,
+			expectedReads: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			scheme := runtime.NewScheme()
+			_ = capi.AddToScheme(scheme)
+			cluster := &capi.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+				Status:     capi.ClusterStatus{Conditions: tc.conditions},
+			}
+			c := &updatingClient{
+				Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build(),
+				updateAt: tc.updateAt,
+				update:   tc.update,
+			}
+
+			options := append([]WaitOption{WithWaitInterval(5 * time.Millisecond)}, tc.options...)
+			err := WaitFor(context.Background(), c, key, &capi.Cluster{}, tc.predicate, options...)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if tc.expectedReads > 0 && c.reads != tc.expectedReads {
+				t.Fatalf("expected %d reads, got %d", tc.expectedReads, c.reads)
+			}
+
+			if err != nil {
+				var waitErr *WaitError
+				if !errors.As(err, &waitErr) {
+					t.Fatalf("expected WaitError, got %#v", err)
+				}
+				if waitErr.Key != key {
+					t.Fatalf("expected key %s, got %s", key, waitErr.Key)
+				}
+				switch {
+				case tc.expectedLastObs == nil && waitErr.LastObserved != nil:
+					t.Fatalf("expected no last observed condition, got %s", sprintCondition(waitErr.LastObserved))
+				case tc.expectedLastObs != nil && (waitErr.LastObserved == nil || waitErr.LastObserved.Status != *tc.expectedLastObs):
+					t.Fatalf("expected last observed condition with status %s, got %v", *tc.expectedLastObs, waitErr.LastObserved)
+				}
+				if !strings.Contains(microerror.Pretty(err, false), tc.expectedMessage) {
+					t.Fatalf("expected error message to contain %q, got %q", tc.expectedMessage, microerror.Pretty(err, false))
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForClientError(t *testing.T) {
+	err := WaitFor(context.Background(), failingClient{}, client.ObjectKey{Name: "test"}, &capi.Cluster{}, ConditionTrue(capi.ReadyCondition))
+	if err == nil || IsWaitTimeout(err) || IsWaitFailed(err) {
+		t.Fatalf("expected client error, got %#v", err)
+	}
+}
+
+func statusPtr(status corev1.ConditionStatus) *corev1.ConditionStatus {
+	return &status
+}
@@ -0,0 +1,308 @@
+// Package conditionstest provides fluent builders for Cluster API objects
+// with conditions, to be used as fixtures in tests.
+//
+// Example:
+//
+//    cluster := conditionstest.NewCluster().
+//        WithCondition(conditions.Creating, conditionstest.False).
+//        Reason(conditions.CreationCompletedReason).
+//        Severity(capi.ConditionSeverityInfo).
+//        TransitionedAgo(15 * time.Minute).
+//        BuildCluster()
+//
+package conditionstest
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+
+	"github.com/giantswarm/conditions/pkg/conditions"
+)
+
+const (
+	// True is a shorthand for corev1.ConditionTrue.
+	True = corev1.ConditionTrue
+
+	// False is a shorthand for corev1.ConditionFalse.
+	False = corev1.ConditionFalse
+
+	// Unknown is a shorthand for corev1.ConditionUnknown.
+	Unknown = corev1.ConditionUnknown
+)
+
+const (
+	// DefaultNamespace is the namespace of built objects when it is not
+	// set with WithNamespace.
+	DefaultNamespace = "org-test"
+)
+
+// Builder builds an object of any kind with conditions. Methods that change
+// the condition, e.g. Reason, change the condition that was added last with
+// WithCondition. Builders for Cluster API kinds are created with NewCluster,
+// NewMachine, NewMachinePool and NewMachineDeployment.
+type Builder struct {
+	object     conditions.Object
+	clock      conditions.Clock
+	conditions capi.Conditions
+
+	// agos contains durations set with TransitionedAgo, which are
+	// converted to LastTransitionTime when the object is built, so that
+	// WithClock can be called in any order.
+	agos map[int]time.Duration
+}
+
+// NewBuilder returns a new builder for a copy of the specified object.
+// Conditions that are already set on the object are replaced with
+// conditions added with WithCondition.
+func NewBuilder(object conditions.Object) *Builder {
+	return &Builder{
+		object:     object.DeepCopyObject().(conditions.Object),
+		clock:      conditions.RealClock{},
+		conditions: capi.Conditions{},
+		agos:       map[int]time.Duration{},
+	}
+}
+
+// NewCluster returns a new builder for a Cluster named "test" in
+// DefaultNamespace, without conditions.
+func NewCluster() *Builder {
+	return NewBuilder(&capi.Cluster{
+		TypeMeta:   typeMeta(capi.GroupVersion.String(), "Cluster"),
+		ObjectMeta: objectMeta(),
+	})
+}
+
+// NewMachine returns a new builder for a Machine named "test" in
+// DefaultNamespace, without conditions.
+func NewMachine() *Builder {
+	return NewBuilder(&capi.Machine{
+		TypeMeta:   typeMeta(capi.GroupVersion.String(), "Machine"),
+		ObjectMeta: objectMeta(),
+	})
+}
+
+// NewMachinePool returns a new builder for a MachinePool named "test" in
+// DefaultNamespace, without conditions.
+func NewMachinePool() *Builder {
+	return NewBuilder(&capiexp.MachinePool{
+		TypeMeta:   typeMeta(capiexp.GroupVersion.String(), "MachinePool"),
+		ObjectMeta: objectMeta(),
+	})
+}
+
+// NewMachineDeployment returns a new builder for a MachineDeployment named
+// "test" in DefaultNamespace, without conditions.
+func NewMachineDeployment() *Builder {
+	return NewBuilder(&capi.MachineDeployment{
+		TypeMeta:   typeMeta(capi.GroupVersion.String(), "MachineDeployment"),
+		ObjectMeta: objectMeta(),
+	})
+}
+
+// WithName sets the object name.
+func (b *Builder) WithName(name string) *Builder {
+	b.object.SetName(name)
+	return b
+}
+
+// WithNamespace sets the object namespace.
+func (b *Builder) WithNamespace(namespace string) *Builder {
+	b.object.SetNamespace(namespace)
+	return b
+}
+
+// WithClusterName sets the cluster name label, and Spec.ClusterName of
+// Machine, MachinePool and MachineDeployment.
+func (b *Builder) WithClusterName(clusterName string) *Builder {
+	switch object := b.object.(type) {
+	case *capi.Machine:
+		object.Spec.ClusterName = clusterName
+	case *capiexp.MachinePool:
+		object.Spec.ClusterName = clusterName
+	case *capi.MachineDeployment:
+		object.Spec.ClusterName = clusterName
+	}
+
+	labels := b.object.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[capi.ClusterLabelName] = clusterName
+	b.object.SetLabels(labels)
+	return b
+}
+
+// WithInfrastructureRef sets Spec.InfrastructureRef of a Cluster. It panics
+// for other kinds.
+func (b *Builder) WithInfrastructureRef(reference *corev1.ObjectReference) *Builder {
+	b.cluster("WithInfrastructureRef").Spec.InfrastructureRef = reference
+	return b
+}
+
+// WithControlPlaneRef sets Spec.ControlPlaneRef of a Cluster. It panics for
+// other kinds.
+func (b *Builder) WithControlPlaneRef(reference *corev1.ObjectReference) *Builder {
+	b.cluster("WithControlPlaneRef").Spec.ControlPlaneRef = reference
+	return b
+}
+
+// WithClock sets the clock that is used to compute LastTransitionTime.
+// Default is conditions.RealClock.
+func (b *Builder) WithClock(clock conditions.Clock) *Builder {
+	b.clock = clock
+	return b
+}
+
+// WithCondition adds a condition with the specified type and status. Reason,
+// Severity, Message, TransitionedAt and TransitionedAgo change the condition
+// that was added last.
+func (b *Builder) WithCondition(conditionType capi.ConditionType, status corev1.ConditionStatus) *Builder {
+	b.conditions = append(b.conditions, capi.Condition{
+		Type:   conditionType,
+		Status: status,
+	})
+	return b
+}
+
+// Reason sets the reason of the condition that was added last.
+func (b *Builder) Reason(reason string) *Builder {
+	b.update(func(condition *capi.Condition) {
+		condition.Reason = reason
+	})
+	return b
+}
+
+// Severity sets the severity of the condition that was added last.
+func (b *Builder) Severity(severity capi.ConditionSeverity) *Builder {
+	b.update(func(condition *capi.Condition) {
+		condition.Severity = severity
+	})
+	return b
+}
+
+// Message sets the message of the condition that was added last.
+func (b *Builder) Message(format string, args ...interface{}) *Builder {
+	b.update(func(condition *capi.Condition) {
+		condition.Message = fmt.Sprintf(format, args...)
+	})
+	return b
+}
+
+// TransitionedAt sets LastTransitionTime of the condition that was added
+// last.
+func (b *Builder) TransitionedAt(t time.Time) *Builder {
+	b.update(func(condition *capi.Condition) {
+		condition.LastTransitionTime = metav1.NewTime(t.UTC().Truncate(time.Second))
+	})
+	delete(b.agos, len(b.conditions)-1)
+	return b
+}
+
+// TransitionedAgo sets LastTransitionTime of the condition that was added
+// last to the specified duration before the object is built.
+func (b *Builder) TransitionedAgo(d time.Duration) *Builder {
+	b.update(func(condition *capi.Condition) {})
+	b.agos[len(b.conditions)-1] = d
+	return b
+}
+
+// Build returns a new object with all added conditions. Conditions without
+// LastTransitionTime get the current time, in the same way as when they are
+// set with capiconditions.Set. Objects of Cluster API kinds can be built
+// with their type with BuildCluster, BuildMachine, BuildMachinePool and
+// BuildMachineDeployment.
+func (b *Builder) Build() conditions.Object {
+	now := b.clock.Now()
+	result := b.conditions.DeepCopy()
+	for i := range result {
+		if ago, ok := b.agos[i]; ok {
+			result[i].LastTransitionTime = metav1.NewTime(now.Add(-ago).UTC().Truncate(time.Second))
+		} else if result[i].LastTransitionTime.IsZero() {
+			result[i].LastTransitionTime = metav1.NewTime(now.UTC().Truncate(time.Second))
+		}
+	}
+
+	object := b.object.DeepCopyObject().(conditions.Object)
+	object.SetConditions(result)
+	return object
+}
+
+// BuildCluster builds a Cluster in the same way as Build. It panics for
+// other kinds.
+func (b *Builder) BuildCluster() *capi.Cluster {
+	cluster, ok := b.Build().(*capi.Cluster)
+	if !ok {
+		panic(fmt.Sprintf("conditionstest: BuildCluster can only be used with Cluster, got %T", b.object))
+	}
+
+	return cluster
+}
+
+// BuildMachine builds a Machine in the same way as Build. It panics for
+// other kinds.
+func (b *Builder) BuildMachine() *capi.Machine {
+	machine, ok := b.Build().(*capi.Machine)
+	if !ok {
+		panic(fmt.Sprintf("conditionstest: BuildMachine can only be used with Machine, got %T", b.object))
+	}
+
+	return machine
+}
+
+// BuildMachinePool builds a MachinePool in the same way as Build. It panics
+// for other kinds.
+func (b *Builder) BuildMachinePool() *capiexp.MachinePool {
+	machinePool, ok := b.Build().(*capiexp.MachinePool)
+	if !ok {
+		panic(fmt.Sprintf("conditionstest: BuildMachinePool can only be used with MachinePool, got %T", b.object))
+	}
+
+	return machinePool
+}
+
+// BuildMachineDeployment builds a MachineDeployment in the same way as
+// Build. It panics for other kinds.
+func (b *Builder) BuildMachineDeployment() *capi.MachineDeployment {
+	machineDeployment, ok := b.Build().(*capi.MachineDeployment)
+	if !ok {
+		panic(fmt.Sprintf("conditionstest: BuildMachineDeployment can only be used with MachineDeployment, got %T", b.object))
+	}
+
+	return machineDeployment
+}
+
+func (b *Builder) update(update func(condition *capi.Condition)) {
+	if len(b.conditions) == 0 {
+		panic("conditionstest: condition must be added with WithCondition before it is changed")
+	}
+
+	update(&b.conditions[len(b.conditions)-1])
+}
+
+func (b *Builder) cluster(method string) *capi.Cluster {
+	cluster, ok := b.object.(*capi.Cluster)
+	if !ok {
+		panic(fmt.Sprintf("conditionstest: %s can only be used with Cluster, got %T", method, b.object))
+	}
+
+	return cluster
+}
+
+func typeMeta(apiVersion, kind string) metav1.TypeMeta {
+	return metav1.TypeMeta{
+		APIVersion: apiVersion,
+		Kind:       kind,
+	}
+}
+
+func objectMeta() metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: DefaultNamespace,
+		Name:      "test",
+	}
+}
@@ -0,0 +1,152 @@
+package conditionstest
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	"github.com/giantswarm/conditions/pkg/conditions"
+)
+
+func TestBuilders(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := conditions.NewFakeClock(now)
+
+	testCases := []struct {
+		name         string
+		object       conditions.Object
+		expectedKind string
+	}{
+		{
+			name: "case 0: Cluster is built with conditions",
+			object: NewCluster().WithClock(clock).
+				WithCondition(conditions.Creating, False).Reason(conditions.CreationCompletedReason).Severity(capi.ConditionSeverityInfo).TransitionedAgo(15*time.Minute).
+				WithCondition(capi.ReadyCondition, True).
+				Build(),
+			expectedKind: "Cluster",
+		},
+		{
+			name: "case 1: Machine is built with conditions",
+			object: NewMachine().WithClock(clock).
+				WithCondition(conditions.Creating, False).Reason(conditions.CreationCompletedReason).Severity(capi.ConditionSeverityInfo).TransitionedAgo(15*time.Minute).
+				WithCondition(capi.ReadyCondition, True).
+				Build(),
+			expectedKind: "Machine",
+		},
+		{
+			name: "case 2: MachinePool is built with conditions",
+			object: NewMachinePool().WithClock(clock).
+				WithCondition(conditions.Creating, False).Reason(conditions.CreationCompletedReason).Severity(capi.ConditionSeverityInfo).TransitionedAgo(15*time.Minute).
+				WithCondition(capi.ReadyCondition, True).
+				Build(),
+			expectedKind: "MachinePool",
+		},
+		{
+			name: "case 3: MachineDeployment is built with conditions",
+			object: NewMachineDeployment().WithClock(clock).
+				WithCondition(conditions.Creating, False).Reason(conditions.CreationCompletedReason).Severity(capi.ConditionSeverityInfo).TransitionedAgo(15*time.Minute).
+				WithCondition(capi.ReadyCondition, True).
+				Build(),
+			expectedKind: "MachineDeployment",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			kind := tc.object.GetObjectKind().GroupVersionKind().Kind
+			if kind != tc.expectedKind {
+				t.Fatalf("expected kind %s, got %s", tc.expectedKind, kind)
+			}
+
+			if !conditions.IsCreatingFalse(tc.object, conditions.WithCreationCompletedReason(), conditions.WithSeverityInfo()) {
+				t.Fatalf("expected Creating condition with status False and reason CreationCompleted")
+			}
+			creating, _ := conditions.GetCreating(tc.object)
+			if !creating.LastTransitionTime.Time.Equal(now.Add(-15 * time.Minute)) {
+				t.Fatalf("expected Creating transitioned 15m ago, got %s", creating.LastTransitionTime)
+			}
+
+			if !conditions.IsReadyTrue(tc.object) {
+				t.Fatalf("expected Ready condition with status True")
+			}
+			ready := tc.object.GetConditions()[1]
+			if !ready.LastTransitionTime.Time.Equal(now) {
+				t.Fatalf("expected Ready transitioned now, got %s", ready.LastTransitionTime)
+			}
+		})
+	}
+}
+
+func TestBuilderIsReusable(t *testing.T) {
+	transitionTime := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	builder := NewMachinePool().
+		WithName("np1").
+		WithClusterName("c1").
+		WithCondition(capi.ReadyCondition, False).Message("%d of %d replicas are ready", 1, 3).TransitionedAt(transitionTime)
+
+	first := builder.BuildMachinePool()
+	first.Status.Conditions[0].Status = corev1.ConditionTrue
+	second := builder.BuildMachinePool()
+
+	if second.Status.Conditions[0].Status != corev1.ConditionFalse {
+		t.Fatalf("expected that changing built object does not change the builder")
+	}
+	if second.Name != "np1" || second.Spec.ClusterName != "c1" || second.Labels[capi.ClusterLabelName] != "c1" {
+		t.Fatalf("expected name np1 and cluster name c1, got %s and %s", second.Name, second.Spec.ClusterName)
+	}
+	if second.Status.Conditions[0].Message != "1 of 3 replicas are ready" {
+		t.Fatalf("expected message to be set, got %q", second.Status.Conditions[0].Message)
+	}
+	if !second.Status.Conditions[0].LastTransitionTime.Time.Equal(transitionTime) {
+		t.Fatalf("expected LastTransitionTime %s, got %s", transitionTime, second.Status.Conditions[0].LastTransitionTime)
+	}
+}
+
+func TestBuilderPanicsWithoutCondition(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic when reason is set without condition")
+		}
+	}()
+
+	NewCluster().Reason("Oops")
+}
+
+func TestBuilderPanicsWithClusterFieldOnOtherKind(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic when infrastructure reference is set on MachinePool")
+		}
+	}()
+
+	NewMachinePool().WithInfrastructureRef(&corev1.ObjectReference{Kind: "AzureCluster", Name: "test"})
+}
+
+func TestTypedBuilders(t *testing.T) {
+	if cluster := NewCluster().WithName("c1").BuildCluster(); cluster.Name != "c1" {
+		t.Fatalf("expected Cluster c1, got %s", cluster.Name)
+	}
+	if machine := NewMachine().WithName("m1").BuildMachine(); machine.Name != "m1" {
+		t.Fatalf("expected Machine m1, got %s", machine.Name)
+	}
+	if machinePool := NewMachinePool().WithName("np1").BuildMachinePool(); machinePool.Name != "np1" {
+		t.Fatalf("expected MachinePool np1, got %s", machinePool.Name)
+	}
+	if machineDeployment := NewMachineDeployment().WithName("np2").BuildMachineDeployment(); machineDeployment.Name != "np2" {
+		t.Fatalf("expected MachineDeployment np2, got %s", machineDeployment.Name)
+	}
+}
+
+func TestTypedBuilderPanicsWithOtherKind(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic when MachinePool is built as Cluster")
+		}
+	}()
+
+	NewMachinePool().BuildCluster()
+}
@@ -0,0 +1,263 @@
+// Package matchers provides gomega matchers for conditions set on Cluster API
+// objects. Matchers use check functions from the conditions package, so they
+// have the same semantics as the conditions package.
+//
+// Example:
+//
+//    Expect(cluster).To(matchers.BeReady())
+//    Expect(cluster).To(matchers.HaveCondition(conditions.Creating).WithStatus(corev1.ConditionFalse).WithReason(conditions.CreationCompletedReason))
+//
+package matchers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+	"github.com/onsi/gomega/types"
+	corev1 "k8s.io/api/core/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+
+	"github.com/giantswarm/conditions/pkg/conditions"
+)
+
+// ConditionMatcher matches objects that have the specified condition set,
+// optionally with the specified status, reason and severity.
+type ConditionMatcher struct {
+	conditionType capi.ConditionType
+	status        corev1.ConditionStatus
+	checkOptions  []conditions.CheckOption
+	descriptions  []string
+}
+
+// HaveCondition returns a matcher that succeeds when the actual object has
+// the condition of the specified type set, with any status.
+func HaveCondition(conditionType capi.ConditionType) *ConditionMatcher {
+	return &ConditionMatcher{
+		conditionType: conditionType,
+	}
+}
+
+// WithStatus returns a copy of the matcher that also checks condition
+// status.
+func (m *ConditionMatcher) WithStatus(status corev1.ConditionStatus) *ConditionMatcher {
+	result := m.copy()
+	result.status = status
+	return result
+}
+
+// WithReason returns a copy of the matcher that also checks condition reason
+// with conditions.WithReason.
+func (m *ConditionMatcher) WithReason(reason string) *ConditionMatcher {
+	return m.with(conditions.WithReason(reason), fmt.Sprintf("reason %s", reason))
+}
+
+// WithSeverity returns a copy of the matcher that also checks condition
+// severity with conditions.WithSeverity.
+func (m *ConditionMatcher) WithSeverity(severity capi.ConditionSeverity) *ConditionMatcher {
+	return m.with(conditions.WithSeverity(severity), fmt.Sprintf("severity %s", severity))
+}
+
+// WithCheck returns a copy of the matcher that also checks the condition
+// with the specified check option, described by the specified text in
+// failure messages.
+func (m *ConditionMatcher) WithCheck(checkOption conditions.CheckOption, description string) *ConditionMatcher {
+	return m.with(checkOption, description)
+}
+
+// Match checks if the actual object has the condition.
+func (m *ConditionMatcher) Match(actual interface{}) (bool, error) {
+	object, err := toObject(actual)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	condition := capiconditions.Get(object, m.conditionType)
+	if condition == nil {
+		return false, nil
+	}
+
+	switch m.status {
+	case corev1.ConditionTrue:
+		if !conditions.IsTrue(condition) {
+			return false, nil
+		}
+	case corev1.ConditionFalse:
+		if !conditions.IsFalse(condition) {
+			return false, nil
+		}
+	case corev1.ConditionUnknown:
+		if !conditions.IsUnknown(condition) {
+			return false, nil
+		}
+	case "":
+		// Status is not checked.
+	default:
+		if condition.Status != m.status {
+			return false, nil
+		}
+	}
+
+	return conditions.All(m.checkOptions...)(condition), nil
+}
+
+// FailureMessage returns the message printed when the matcher has failed.
+func (m *ConditionMatcher) FailureMessage(actual interface{}) string {
+	return failureMessage(actual, "to have", m.describe())
+}
+
+// NegatedFailureMessage returns the message printed when the negated
+// matcher has failed.
+func (m *ConditionMatcher) NegatedFailureMessage(actual interface{}) string {
+	return failureMessage(actual, "not to have", m.describe())
+}
+
+func (m *ConditionMatcher) copy() *ConditionMatcher {
+	return &ConditionMatcher{
+		conditionType: m.conditionType,
+		status:        m.status,
+		checkOptions:  append([]conditions.CheckOption{}, m.checkOptions...),
+		descriptions:  append([]string{}, m.descriptions...),
+	}
+}
+
+func (m *ConditionMatcher) with(checkOption conditions.CheckOption, description string) *ConditionMatcher {
+	result := m.copy()
+	result.checkOptions = append(result.checkOptions, checkOption)
+	result.descriptions = append(result.descriptions, description)
+	return result
+}
+
+func (m *ConditionMatcher) describe() string {
+	description := fmt.Sprintf("condition %s", m.conditionType)
+	var details []string
+	if m.status != "" {
+		details = append(details, fmt.Sprintf("status %s", m.status))
+	}
+	details = append(details, m.descriptions...)
+	if len(details) > 0 {
+		description += " with " + strings.Join(details, " and ")
+	}
+
+	return description
+}
+
+// objectMatcher matches objects with a check function from the conditions
+// package.
+type objectMatcher struct {
+	description string
+	check       func(object conditions.Object) bool
+}
+
+// BeReady returns a matcher that succeeds when the actual object has Ready
+// condition with status True, see conditions.IsReadyTrue.
+func BeReady() types.GomegaMatcher {
+	return &objectMatcher{
+		description: "condition Ready with status True",
+		check:       conditions.IsReadyTrue,
+	}
+}
+
+// BeCreating returns a matcher that succeeds when the actual object has
+// Creating condition with status True, see conditions.IsCreatingTrue.
+func BeCreating() types.GomegaMatcher {
+	return &objectMatcher{
+		description: "condition Creating with status True",
+		check:       conditions.IsCreatingTrue,
+	}
+}
+
+// HaveCompletedCreation returns a matcher that succeeds when the actual
+// object has Creating condition with status False and reason
+// CreationCompleted.
+func HaveCompletedCreation() types.GomegaMatcher {
+	return &objectMatcher{
+		description: "condition Creating with status False and reason CreationCompleted",
+		check: func(object conditions.Object) bool {
+			return conditions.IsCreatingFalse(object, conditions.WithCreationCompletedReason())
+		},
+	}
+}
+
+// BeUpgrading returns a matcher that succeeds when the actual object has
+// Upgrading condition with status True, see conditions.IsUpgradingTrue.
+func BeUpgrading() types.GomegaMatcher {
+	return &objectMatcher{
+		description: "condition Upgrading with status True",
+		check:       conditions.IsUpgradingTrue,
+	}
+}
+
+// HaveCompletedUpgrade returns a matcher that succeeds when the actual
+// object has Upgrading condition with status False and reason
+// UpgradeCompleted.
+func HaveCompletedUpgrade() types.GomegaMatcher {
+	return &objectMatcher{
+		description: "condition Upgrading with status False and reason UpgradeCompleted",
+		check: func(object conditions.Object) bool {
+			return conditions.IsUpgradingFalse(object, conditions.WithUpgradeCompletedReason())
+		},
+	}
+}
+
+// Match checks the actual object.
+func (m *objectMatcher) Match(actual interface{}) (bool, error) {
+	object, err := toObject(actual)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	return m.check(object), nil
+}
+
+// FailureMessage returns the message printed when the matcher has failed.
+func (m *objectMatcher) FailureMessage(actual interface{}) string {
+	return failureMessage(actual, "to have", m.description)
+}
+
+// NegatedFailureMessage returns the message printed when the negated
+// matcher has failed.
+func (m *objectMatcher) NegatedFailureMessage(actual interface{}) string {
+	return failureMessage(actual, "not to have", m.description)
+}
+
+func toObject(actual interface{}) (conditions.Object, error) {
+	object, ok := actual.(conditions.Object)
+	if !ok || isNil(actual) {
+		return nil, microerror.Maskf(wrongTypeError, "expected conditions.Object, got %T", actual)
+	}
+
+	return object, nil
+}
+
+// isNil returns true for nil and for typed nil pointers, e.g. a nil
+// *capi.Cluster, on which getting conditions would panic.
+func isNil(actual interface{}) bool {
+	if actual == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(actual)
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
+
+// failureMessage returns a failure message that contains all conditions of
+// the actual object.
+func failureMessage(actual interface{}, verb string, description string) string {
+	object, err := toObject(actual)
+	if err != nil {
+		return fmt.Sprintf("Expected\n\t%T\n%s %s", actual, verb, description)
+	}
+
+	var lines []string
+	for _, condition := range object.GetConditions() {
+		lines = append(lines, "\t"+conditions.SprintCondition(condition))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "\t<none>")
+	}
+
+	return fmt.Sprintf("Expected\n\t%T %s/%s\n%s %s\nconditions:\n%s", object, object.GetNamespace(), object.GetName(), verb, description, strings.Join(lines, "\n"))
+}
@@ -0,0 +1,175 @@
+package matchers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	"github.com/giantswarm/conditions/pkg/conditions"
+)
+
+func testCluster() *capi.Cluster {
+	return &capi.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "org-test", Name: "test"},
+		Status: capi.ClusterStatus{
+			Conditions: capi.Conditions{
+				{Type: capi.ReadyCondition, Status: corev1.ConditionFalse, Reason: "NodesNotReady", Severity: capi.ConditionSeverityWarning, Message: "0 of 3 nodes are ready"},
+				{Type: conditions.Creating, Status: corev1.ConditionFalse, Reason: conditions.CreationCompletedReason},
+				{Type: conditions.Upgrading, Status: corev1.ConditionFalse, Reason: conditions.UpgradeCompletedReason},
+			},
+		},
+	}
+}
+
+func TestMatchers(t *testing.T) {
+	testCases := []struct {
+		name          string
+		matcher       types.GomegaMatcher
+		actual        interface{}
+		expectedMatch bool
+		expectedError bool
+	}{
+		{
+			name:          "case 0: HaveCondition matches set condition",
+			matcher:       HaveCondition(capi.ReadyCondition),
+			actual:        testCluster(),
+			expectedMatch: true,
+		},
+		{
+			name:          "case 1: HaveCondition does not match condition that is not set",
+			matcher:       HaveCondition(conditions.InfrastructureReady),
+			actual:        testCluster(),
+			expectedMatch: false,
+		},
+		{
+			name:          "case 2: HaveCondition matches status, reason and severity",
+			matcher:       HaveCondition(capi.ReadyCondition).WithStatus(corev1.ConditionFalse).WithReason("NodesNotReady").WithSeverity(capi.ConditionSeverityWarning),
+			actual:        testCluster(),
+			expectedMatch: true,
+		},
+		{
+			name:          "case 3: HaveCondition does not match different status",
+			matcher:       HaveCondition(capi.ReadyCondition).WithStatus(corev1.ConditionTrue),
+			actual:        testCluster(),
+			expectedMatch: false,
+		},
+		{
+			name:          "case 4: HaveCondition does not match different severity",
+			matcher:       HaveCondition(capi.ReadyCondition).WithSeverity(capi.ConditionSeverityError),
+			actual:        testCluster(),
+			expectedMatch: false,
+		},
+		{
+			name:          "case 5: HaveCondition matches custom check",
+			matcher:       HaveCondition(capi.ReadyCondition).WithCheck(conditions.WithMinSeverity(capi.ConditionSeverityInfo), "severity at least Info"),
+			actual:        testCluster(),
+			expectedMatch: true,
+		},
+		{
+			name:          "case 6: BeReady does not match cluster that is not ready",
+			matcher:       BeReady(),
+			actual:        testCluster(),
+			expectedMatch: false,
+		},
+		{
+			name:          "case 7: BeCreating does not match created cluster",
+			matcher:       BeCreating(),
+			actual:        testCluster(),
+			expectedMatch: false,
+		},
+		{
+			name:          "case 8: HaveCompletedCreation matches created cluster",
+			matcher:       HaveCompletedCreation(),
+			actual:        testCluster(),
+			expectedMatch: true,
+		},
+		{
+			name:          "case 9: HaveCompletedUpgrade matches upgraded cluster",
+			matcher:       HaveCompletedUpgrade(),
+			actual:        testCluster(),
+			expectedMatch: true,
+		},
+		{
+			name:          "case 10: BeUpgrading does not match upgraded cluster",
+			matcher:       BeUpgrading(),
+			actual:        testCluster(),
+			expectedMatch: false,
+		},
+		{
+			name:          "case 11: Matcher returns error for value that is not an object",
+			matcher:       BeReady(),
+			actual:        "cluster",
+			expectedError: true,
+		},
+		{
+			name:          "case 12: Matcher returns error for typed nil object",
+			matcher:       HaveCondition(capi.ReadyCondition),
+			actual:        (*capi.Cluster)(nil),
+			expectedError: true,
+		},
+		{
+			name:          "case 13: HaveCondition does not match unknown status value",
+			matcher:       HaveCondition(capi.ReadyCondition).WithStatus("Bogus"),
+			actual:        testCluster(),
+			expectedMatch: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			match, err := tc.matcher.Match(tc.actual)
+			if tc.expectedError {
+				if !IsWrongType(err) {
+					t.Fatalf("expected wrong type error, got %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %#v", err)
+			}
+
+			if match != tc.expectedMatch {
+				t.Logf("expected match %t, got %t", tc.expectedMatch, match)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestFailureMessage(t *testing.T) {
+	matcher := HaveCondition(capi.ReadyCondition).WithStatus(corev1.ConditionTrue).WithReason("Ready")
+	message := matcher.FailureMessage(testCluster())
+
+	expected := []string{
+		"*v1beta1.Cluster org-test/test",
+		"to have condition Ready with status True and reason Ready",
+		`Ready: Status=False, Reason=NodesNotReady, Severity=Warning, Message="0 of 3 nodes are ready"`,
+		"Creating: Status=False, Reason=CreationCompleted",
+		"Upgrading: Status=False, Reason=UpgradeCompleted",
+	}
+	for _, text := range expected {
+		if !strings.Contains(message, text) {
+			t.Fatalf("expected failure message to contain %q, got:\n%s", text, message)
+		}
+	}
+
+	negated := BeCreating().NegatedFailureMessage(testCluster())
+	if !strings.Contains(negated, "not to have condition Creating with status True") {
+		t.Fatalf("expected negated failure message, got:\n%s", negated)
+	}
+}
+
+func TestMatchersWithGomega(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	g.Expect(testCluster()).To(HaveCompletedUpgrade())
+	g.Expect(testCluster()).NotTo(BeReady())
+	g.Expect(testCluster()).To(HaveCondition(conditions.Creating).WithStatus(corev1.ConditionFalse).WithReason(conditions.CreationCompletedReason))
+}
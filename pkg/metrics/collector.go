@@ -0,0 +1,236 @@
+// Package metrics provides a Prometheus collector that exports conditions
+// of Cluster API objects.
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	"github.com/giantswarm/conditions/pkg/conditions"
+)
+
+const (
+	labelKind      = "kind"
+	labelNamespace = "namespace"
+	labelName      = "name"
+	labelType      = "type"
+	labelStatus    = "status"
+	labelReason    = "reason"
+	labelSeverity  = "severity"
+)
+
+// DefaultListTimeout is the default timeout for listing objects on every
+// scrape.
+const DefaultListTimeout = 10 * time.Second
+
+// Lister lists objects whose conditions are exported by the Collector.
+type Lister interface {
+	List(ctx context.Context) ([]conditions.Object, error)
+}
+
+// ListerFunc is an adapter that allows using an ordinary function as Lister.
+type ListerFunc func(ctx context.Context) ([]conditions.Object, error)
+
+// List calls f(ctx).
+func (f ListerFunc) List(ctx context.Context) ([]conditions.Object, error) {
+	return f(ctx)
+}
+
+// Config is the Collector configuration.
+type Config struct {
+	// Lister lists objects whose conditions are exported. It is called on
+	// every scrape.
+	Lister Lister
+
+	// Clock is used to compute how long objects have been in Creating or
+	// Upgrading condition. It defaults to conditions.RealClock.
+	Clock conditions.Clock
+
+	// Namespace is an optional prefix for all metric names.
+	Namespace string
+
+	// Timeout is the timeout for listing objects, so that a slow API server
+	// does not block the scrape. It defaults to DefaultListTimeout.
+	Timeout time.Duration
+}
+
+// Collector is a prometheus.Collector that exports the following metrics for
+// all objects returned by the configured Lister:
+//
+//   - condition_status{kind,namespace,name,type,status,reason,severity}, a
+//     gauge with value 1 for every condition set on an object.
+//   - condition_in_progress_duration_seconds{kind,type}, a histogram of how
+//     long objects have been in Creating or Upgrading condition with status
+//     True, derived from condition LastTransitionTime.
+//
+type Collector struct {
+	lister  Lister
+	clock   conditions.Clock
+	timeout time.Duration
+
+	statusDesc   *prometheus.Desc
+	durationDesc *prometheus.Desc
+}
+
+// New returns a new Collector.
+func New(config Config) (*Collector, error) {
+	if config.Lister == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Lister must not be empty", config)
+	}
+	if config.Timeout < 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Timeout must not be negative", config)
+	}
+	if config.Clock == nil {
+		config.Clock = conditions.RealClock{}
+	}
+	if config.Timeout == 0 {
+		config.Timeout = DefaultListTimeout
+	}
+
+	c := &Collector{
+		lister:  config.Lister,
+		clock:   config.Clock,
+		timeout: config.Timeout,
+
+		statusDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(config.Namespace, "", "condition_status"),
+			"Condition set on an object, with value 1.",
+			[]string{labelKind, labelNamespace, labelName, labelType, labelStatus, labelReason, labelSeverity},
+			nil,
+		),
+		durationDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(config.Namespace, "", "condition_in_progress_duration_seconds"),
+			"Time that objects have spent in Creating or Upgrading condition with status True.",
+			[]string{labelKind, labelType},
+			nil,
+		),
+	}
+
+	return c, nil
+}
+
+// inProgressDurationBuckets are histogram buckets for durations of creation
+// and upgrade, from 1 minute to 4 hours.
+var inProgressDurationBuckets = []float64{60, 300, 600, 1200, 1800, 3600, 7200, 14400}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.statusDesc
+	ch <- c.durationDesc
+}
+
+// Collect implements prometheus.Collector. Objects with the same kind,
+// namespace and name are collected only once, since repeated label sets
+// would make gathering fail. Creating and Upgrading conditions without
+// LastTransitionTime are not observed in the duration histogram.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	objects, err := c.lister.List(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.statusDesc, err)
+		return
+	}
+
+	histograms := map[histogramKey]*histogram{}
+	collected := map[objectKey]bool{}
+	now := c.clock.Now()
+	for _, object := range objects {
+		kind := conditions.KindOf(object)
+
+		id := objectKey{kind: kind, namespace: object.GetNamespace(), name: object.GetName()}
+		if collected[id] {
+			continue
+		}
+		collected[id] = true
+
+		for _, condition := range object.GetConditions() {
+			ch <- prometheus.MustNewConstMetric(
+				c.statusDesc,
+				prometheus.GaugeValue,
+				1,
+				kind,
+				object.GetNamespace(),
+				object.GetName(),
+				string(condition.Type),
+				string(condition.Status),
+				condition.Reason,
+				string(condition.Severity),
+			)
+
+			if condition.Status != corev1.ConditionTrue || !isInProgressCondition(condition.Type) {
+				continue
+			}
+			if condition.LastTransitionTime.IsZero() {
+				// Duration is not known, and time since zero time would
+				// distort the histogram sum.
+				continue
+			}
+
+			key := histogramKey{kind: kind, conditionType: condition.Type}
+			if histograms[key] == nil {
+				histograms[key] = newHistogram()
+			}
+			histograms[key].observe(now.Sub(condition.LastTransitionTime.Time).Seconds())
+		}
+	}
+
+	for key, h := range histograms {
+		ch <- prometheus.MustNewConstHistogram(
+			c.durationDesc,
+			h.count,
+			h.sum,
+			h.buckets,
+			key.kind,
+			string(key.conditionType),
+		)
+	}
+}
+
+func isInProgressCondition(conditionType capi.ConditionType) bool {
+	return conditionType == conditions.Creating || conditionType == conditions.Upgrading
+}
+
+type objectKey struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+type histogramKey struct {
+	kind          string
+	conditionType capi.ConditionType
+}
+
+type histogram struct {
+	count   uint64
+	sum     float64
+	buckets map[float64]uint64
+}
+
+func newHistogram() *histogram {
+	h := &histogram{
+		buckets: map[float64]uint64{},
+	}
+	for _, bucket := range inProgressDurationBuckets {
+		h.buckets[bucket] = 0
+	}
+
+	return h
+}
+
+func (h *histogram) observe(value float64) {
+	h.count++
+	h.sum += value
+	for _, bucket := range inProgressDurationBuckets {
+		if value <= bucket {
+			h.buckets[bucket]++
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+
+	"github.com/giantswarm/conditions/pkg/conditions"
+)
+
+func TestCollector(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	objects := []conditions.Object{
+		&capi.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "org-test", Name: "test"},
+			Status: capi.ClusterStatus{
+				Conditions: capi.Conditions{
+					{Type: capi.ReadyCondition, Status: corev1.ConditionFalse, Reason: "Provisioning", Severity: capi.ConditionSeverityInfo},
+					{Type: conditions.Creating, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(now.Add(-15 * time.Minute))},
+				},
+			},
+		},
+		&capiexp.MachinePool{
+			TypeMeta:   metav1.TypeMeta{Kind: "MachinePool"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "org-test", Name: "np1"},
+			Status: capiexp.MachinePoolStatus{
+				Conditions: capi.Conditions{
+					{Type: conditions.Upgrading, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(now.Add(-2 * time.Minute))},
+				},
+			},
+		},
+	}
+
+	collector, err := New(Config{
+		Lister: ListerFunc(func(ctx context.Context) ([]conditions.Object, error) {
+			return objects, nil
+		}),
+		Clock: conditions.NewFakeClock(now),
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %#v", err)
+	}
+
+	expected := `
+# HELP condition_status Condition set on an object, with value 1.
+# TYPE condition_status gauge
+condition_status{kind="Cluster",name="test",namespace="org-test",reason="",severity="",status="True",type="Creating"} 1
+condition_status{kind="Cluster",name="test",namespace="org-test",reason="Provisioning",severity="Info",status="False",type="Ready"} 1
+condition_status{kind="MachinePool",name="np1",namespace="org-test",reason="",severity="",status="True",type="Upgrading"} 1
+# HELP condition_in_progress_duration_seconds Time that objects have spent in Creating or Upgrading condition with status True.
+# TYPE condition_in_progress_duration_seconds histogram
+condition_in_progress_duration_seconds_bucket{kind="Cluster",type="Creating",le="60"} 0
+condition_in_progress_duration_seconds_bucket{kind="Cluster",type="Creating",le="300"} 0
+condition_in_progress_duration_seconds_bucket{kind="Cluster",type="Creating",le="600"} 0
+condition_in_progress_duration_seconds_bucket{kind="Cluster",type="Creating",le="1200"} 1
+condition_in_progress_duration_seconds_bucket{kind="Cluster",type="Creating",le="1800"} 1
+condition_in_progress_duration_seconds_bucket{kind="Cluster",type="Creating",le="3600"} 1
+condition_in_progress_duration_seconds_bucket{kind="Cluster",type="Creating",le="7200"} 1
+condition_in_progress_duration_seconds_bucket{kind="Cluster",type="Creating",le="14400"} 1
+condition_in_progress_duration_seconds_bucket{kind="Cluster",type="Creating",le="+Inf"} 1
+condition_in_progress_duration_seconds_sum{kind="Cluster",type="Creating"} 900
+condition_in_progress_duration_seconds_count{kind="Cluster",type="Creating"} 1
+condition_in_progress_duration_seconds_bucket{kind="MachinePool",type="Upgrading",le="60"} 0
+condition_in_progress_duration_seconds_bucket{kind="MachinePool",type="Upgrading",le="300"} 1
+condition_in_progress_duration_seconds_bucket{kind="MachinePool",type="Upgrading",le="600"} 1
+condition_in_progress_duration_seconds_bucket{kind="MachinePool",type="Upgrading",le="1200"} 1
+condition_in_progress_duration_seconds_bucket{kind="MachinePool",type="Upgrading",le="1800"} 1
+condition_in_progress_duration_seconds_bucket{kind="MachinePool",type="Upgrading",le="3600"} 1
+condition_in_progress_duration_seconds_bucket{kind="MachinePool",type="Upgrading",le="7200"} 1
+condition_in_progress_duration_seconds_bucket{kind="MachinePool",type="Upgrading",le="14400"} 1
+condition_in_progress_duration_seconds_bucket{kind="MachinePool",type="Upgrading",le="+Inf"} 1
+condition_in_progress_duration_seconds_sum{kind="MachinePool",type="Upgrading"} 120
+condition_in_progress_duration_seconds_count{kind="MachinePool",type="Upgrading"} 1
+`
+
+	err = testutil.CollectAndCompare(collector, strings.NewReader(expected))
+	if err != nil {
+		t.Fatalf("unexpected metrics: %s", err)
+	}
+}
+
+func TestCollectorSkipsDuplicatesAndMissingTransitionTime(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	newCluster := func() conditions.Object {
+		return &capi.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "org-test", Name: "test"},
+			Status: capi.ClusterStatus{
+				Conditions: capi.Conditions{
+					{Type: conditions.Creating, Status: corev1.ConditionTrue},
+				},
+			},
+		}
+	}
+
+	collector, err := New(Config{
+		Lister: ListerFunc(func(ctx context.Context) ([]conditions.Object, error) {
+			return []conditions.Object{newCluster(), newCluster()}, nil
+		}),
+		Clock: conditions.NewFakeClock(now),
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %#v", err)
+	}
+
+	expected := `
+# HELP condition_status Condition set on an object, with value 1.
+# TYPE condition_status gauge
+condition_status{kind="Cluster",name="test",namespace="org-test",reason="",severity="",status="True",type="Creating"} 1
+`
+
+	err = testutil.CollectAndCompare(collector, strings.NewReader(expected))
+	if err != nil {
+		t.Fatalf("unexpected metrics: %s", err)
+	}
+}
+
+func TestCollectorListerError(t *testing.T) {
+	collector, err := New(Config{
+		Lister: ListerFunc(func(ctx context.Context) ([]conditions.Object, error) {
+			return nil, errors.New("cannot list")
+		}),
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %#v", err)
+	}
+
+	err = testutil.CollectAndCompare(collector, strings.NewReader(""))
+	if err == nil {
+		t.Fatalf("expected error for failing lister")
+	}
+}
+
+func TestCollectorListerTimeout(t *testing.T) {
+	collector, err := New(Config{
+		Lister: ListerFunc(func(ctx context.Context) ([]conditions.Object, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}),
+		Timeout: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %#v", err)
+	}
+
+	err = testutil.CollectAndCompare(collector, strings.NewReader(""))
+	if err == nil {
+		t.Fatalf("expected error for lister that timed out")
+	}
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	_, err := New(Config{})
+	if !IsInvalidConfig(err) {
+		t.Fatalf("expected invalid config error, got %#v", err)
+	}
+
+	_, err = New(Config{
+		Lister:  ListerFunc(func(ctx context.Context) ([]conditions.Object, error) { return nil, nil }),
+		Timeout: -time.Second,
+	})
+	if !IsInvalidConfig(err) {
+		t.Fatalf("expected invalid config error for negative timeout, got %#v", err)
+	}
+}
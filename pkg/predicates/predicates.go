@@ -0,0 +1,186 @@
+// Package predicates provides controller-runtime predicates that filter
+// events by changes of conditions set on Cluster API objects. Predicates use
+// check functions from the conditions package, so they have the same
+// semantics as the conditions package.
+//
+// Objects must implement capiconditions.Getter, like Cluster, MachinePool
+// and other Cluster API objects do. Unstructured objects, e.g. provider CRs
+// that are watched as unstructured objects, are read with
+// conditions.NewUnstructuredObject. Events for objects of other types are
+// not filtered.
+//
+// Example:
+//
+//    err := ctrl.NewControllerManagedBy(mgr).
+//        For(&capi.Cluster{}, builder.WithPredicates(predicates.ConditionChanged(capi.ReadyCondition, conditions.Upgrading))).
+//        Owns(&capiexp.MachinePool{}, builder.WithPredicates(predicates.ConditionBecame(capi.ReadyCondition, corev1.ConditionTrue))).
+//        Complete(r)
+//
+package predicates
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/giantswarm/conditions/pkg/conditions"
+)
+
+// ConditionChanged returns a predicate that accepts update events in which
+// any of the specified conditions has changed, i.e. when old and new
+// conditions are not equivalent (see conditions.AreEquivalent). Changes of
+// only message or LastTransitionTime are ignored. When no condition types
+// are specified, all conditions set on old or new object are compared.
+//
+// Create, delete and generic events are always accepted.
+func ConditionChanged(conditionTypes ...capi.ConditionType) predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldGetter, newGetter, ok := gettersOf(e)
+			if !ok {
+				return true
+			}
+
+			types := conditionTypes
+			if len(types) == 0 {
+				types = conditionTypesOf(oldGetter, newGetter)
+			}
+
+			for _, conditionType := range types {
+				if !conditions.AreEquivalent(capiconditions.Get(oldGetter, conditionType), capiconditions.Get(newGetter, conditionType)) {
+					return true
+				}
+			}
+
+			return false
+		},
+	}
+}
+
+// ConditionBecame returns a predicate that accepts update events in which
+// the condition of the specified type got the specified status and all
+// optionally specified checks became successful, while before the update
+// the condition did not have that status or some of the checks were not
+// successful. The condition must be set on the new object, so a condition
+// that is removed does not become Unknown.
+//
+// Create and generic events are accepted when the condition on the object
+// has the specified status and all checks are successful. Delete events are
+// never accepted.
+//
+// Example:
+//
+//    // Accept only events in which the cluster started to upgrade.
+//    predicates.ConditionBecame(conditions.Upgrading, corev1.ConditionTrue)
+//
+//    // Accept only events in which Ready became False with severity Error.
+//    predicates.ConditionBecame(capi.ReadyCondition, corev1.ConditionFalse, conditions.WithSeverityError())
+//
+func ConditionBecame(conditionType capi.ConditionType, status corev1.ConditionStatus, checkOptions ...conditions.CheckOption) predicate.Predicate {
+	matches := func(getter capiconditions.Getter) bool {
+		condition := capiconditions.Get(getter, conditionType)
+		return condition != nil &&
+			condition.Status == status &&
+			conditions.All(checkOptions...)(condition)
+	}
+
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			getter, ok := getterOf(e.Object)
+			return !ok || matches(getter)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldGetter, newGetter, ok := gettersOf(e)
+			if !ok {
+				return true
+			}
+
+			return matches(newGetter) && !matches(oldGetter)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			getter, ok := getterOf(e.Object)
+			return !ok || matches(getter)
+		},
+	}
+}
+
+// ReasonChanged returns a predicate that accepts update events in which the
+// reason of the condition of the specified type has changed. Condition that
+// is not set is handled as if it had an empty reason, so setting or
+// removing a condition with a reason is also a change.
+//
+// Create, delete and generic events are always accepted.
+func ReasonChanged(conditionType capi.ConditionType) predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldGetter, newGetter, ok := gettersOf(e)
+			if !ok {
+				return true
+			}
+
+			return reasonOf(oldGetter, conditionType) != reasonOf(newGetter, conditionType)
+		},
+	}
+}
+
+// getterOf returns the conditions getter for the specified object and true,
+// or false when conditions cannot be read from objects of that type.
+func getterOf(object client.Object) (capiconditions.Getter, bool) {
+	switch o := object.(type) {
+	case capiconditions.Getter:
+		return o, true
+	case *unstructured.Unstructured:
+		if o == nil {
+			return nil, false
+		}
+		return conditions.NewUnstructuredObject(o), true
+	default:
+		return nil, false
+	}
+}
+
+func gettersOf(e event.UpdateEvent) (capiconditions.Getter, capiconditions.Getter, bool) {
+	oldGetter, ok := getterOf(e.ObjectOld)
+	if !ok {
+		return nil, nil, false
+	}
+
+	newGetter, ok := getterOf(e.ObjectNew)
+	if !ok {
+		return nil, nil, false
+	}
+
+	return oldGetter, newGetter, true
+}
+
+func conditionTypesOf(getters ...capiconditions.Getter) []capi.ConditionType {
+	var types []capi.ConditionType
+	seen := map[capi.ConditionType]bool{}
+	for _, getter := range getters {
+		for _, condition := range getter.GetConditions() {
+			if seen[condition.Type] {
+				continue
+			}
+			seen[condition.Type] = true
+			types = append(types, condition.Type)
+		}
+	}
+
+	return types
+}
+
+func reasonOf(getter capiconditions.Getter, conditionType capi.ConditionType) string {
+	condition := capiconditions.Get(getter, conditionType)
+	if condition == nil {
+		return ""
+	}
+
+	return condition.Reason
+}
@@ -0,0 +1,233 @@
+package predicates
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/giantswarm/conditions/pkg/conditions"
+	"github.com/giantswarm/conditions/pkg/conditionstest"
+)
+
+func TestUpdatePredicates(t *testing.T) {
+	readyFalse := conditionstest.NewCluster().
+		WithCondition(capi.ReadyCondition, conditionstest.False).Reason("NodesNotReady").Severity(capi.ConditionSeverityWarning).Message("0 of 3 nodes are ready").
+		WithCondition(conditions.Upgrading, conditionstest.False).Reason(conditions.UpgradeCompletedReason).
+		Build()
+	readyFalseOtherMessage := conditionstest.NewCluster().
+		WithCondition(capi.ReadyCondition, conditionstest.False).Reason("NodesNotReady").Severity(capi.ConditionSeverityWarning).Message("1 of 3 nodes are ready").
+		WithCondition(conditions.Upgrading, conditionstest.False).Reason(conditions.UpgradeCompletedReason).
+		Build()
+	readyFalseError := conditionstest.NewCluster().
+		WithCondition(capi.ReadyCondition, conditionstest.False).Reason("NodesNotReady").Severity(capi.ConditionSeverityError).
+		WithCondition(conditions.Upgrading, conditionstest.False).Reason(conditions.UpgradeCompletedReason).
+		Build()
+	readyTrue := conditionstest.NewCluster().
+		WithCondition(capi.ReadyCondition, conditionstest.True).
+		WithCondition(conditions.Upgrading, conditionstest.False).Reason(conditions.UpgradeCompletedReason).
+		Build()
+	upgrading := conditionstest.NewCluster().
+		WithCondition(capi.ReadyCondition, conditionstest.False).Reason("NodesNotReady").Severity(capi.ConditionSeverityWarning).Message("0 of 3 nodes are ready").
+		WithCondition(conditions.Upgrading, conditionstest.True).
+		Build()
+	withoutConditions := conditionstest.NewCluster().Build()
+
+	testCases := []struct {
+		name           string
+		predicate      predicate.Predicate
+		oldObject      client.Object
+		newObject      client.Object
+		expectedResult bool
+	}{
+		{
+			name:           "case 0: ConditionChanged accepts status change",
+			predicate:      ConditionChanged(capi.ReadyCondition),
+			oldObject:      readyFalse,
+			newObject:      readyTrue,
+			expectedResult: true,
+		},
+		{
+			name:           "case 1: ConditionChanged ignores message change",
+			predicate:      ConditionChanged(capi.ReadyCondition),
+			oldObject:      readyFalse,
+			newObject:      readyFalseOtherMessage,
+			expectedResult: false,
+		},
+		{
+			name:           "case 2: ConditionChanged accepts severity change",
+			predicate:      ConditionChanged(capi.ReadyCondition),
+			oldObject:      readyFalse,
+			newObject:      readyFalseError,
+			expectedResult: true,
+		},
+		{
+			name:           "case 3: ConditionChanged ignores change of other condition",
+			predicate:      ConditionChanged(capi.ReadyCondition),
+			oldObject:      readyFalse,
+			newObject:      upgrading,
+			expectedResult: false,
+		},
+		{
+			name:           "case 4: ConditionChanged without types accepts change of any condition",
+			predicate:      ConditionChanged(),
+			oldObject:      readyFalse,
+			newObject:      upgrading,
+			expectedResult: true,
+		},
+		{
+			name:           "case 5: ConditionChanged without types accepts removed conditions",
+			predicate:      ConditionChanged(),
+			oldObject:      readyFalse,
+			newObject:      withoutConditions,
+			expectedResult: true,
+		},
+		{
+			name:           "case 6: ConditionChanged without types ignores unchanged conditions",
+			predicate:      ConditionChanged(),
+			oldObject:      readyFalse,
+			newObject:      readyFalseOtherMessage,
+			expectedResult: false,
+		},
+		{
+			name:           "case 7: ConditionBecame accepts condition that got the status",
+			predicate:      ConditionBecame(capi.ReadyCondition, corev1.ConditionTrue),
+			oldObject:      readyFalse,
+			newObject:      readyTrue,
+			expectedResult: true,
+		},
+		{
+			name:           "case 8: ConditionBecame ignores condition that already had the status",
+			predicate:      ConditionBecame(capi.ReadyCondition, corev1.ConditionFalse),
+			oldObject:      readyFalse,
+			newObject:      readyFalseOtherMessage,
+			expectedResult: false,
+		},
+		{
+			name:           "case 9: ConditionBecame accepts condition for which check became successful",
+			predicate:      ConditionBecame(capi.ReadyCondition, corev1.ConditionFalse, conditions.WithSeverityError()),
+			oldObject:      readyFalse,
+			newObject:      readyFalseError,
+			expectedResult: true,
+		},
+		{
+			name:           "case 10: ConditionBecame ignores condition for which check is not successful",
+			predicate:      ConditionBecame(capi.ReadyCondition, corev1.ConditionFalse, conditions.WithSeverityError()),
+			oldObject:      readyTrue,
+			newObject:      readyFalse,
+			expectedResult: false,
+		},
+		{
+			name:           "case 11: ConditionBecame ignores removed condition",
+			predicate:      ConditionBecame(capi.ReadyCondition, corev1.ConditionUnknown),
+			oldObject:      readyFalse,
+			newObject:      withoutConditions,
+			expectedResult: false,
+		},
+		{
+			name:           "case 12: ReasonChanged accepts reason change",
+			predicate:      ReasonChanged(capi.ReadyCondition),
+			oldObject:      readyFalse,
+			newObject:      readyTrue,
+			expectedResult: true,
+		},
+		{
+			name:           "case 13: ReasonChanged ignores severity change",
+			predicate:      ReasonChanged(capi.ReadyCondition),
+			oldObject:      readyFalse,
+			newObject:      readyFalseError,
+			expectedResult: false,
+		},
+		{
+			name:           "case 14: ReasonChanged accepts removed condition with reason",
+			predicate:      ReasonChanged(capi.ReadyCondition),
+			oldObject:      readyFalse,
+			newObject:      withoutConditions,
+			expectedResult: true,
+		},
+		{
+			name:           "case 15: ConditionChanged works with MachinePool",
+			predicate:      ConditionChanged(capi.ReadyCondition),
+			oldObject:      conditionstest.NewMachinePool().WithCondition(capi.ReadyCondition, conditionstest.False).Reason("ScalingUp").Build(),
+			newObject:      conditionstest.NewMachinePool().WithCondition(capi.ReadyCondition, conditionstest.True).Build(),
+			expectedResult: true,
+		},
+		{
+			name:           "case 16: ConditionBecame works with unstructured objects",
+			predicate:      ConditionBecame(capi.ReadyCondition, corev1.ConditionTrue),
+			oldObject:      toUnstructured(t, readyFalse),
+			newObject:      toUnstructured(t, readyTrue),
+			expectedResult: true,
+		},
+		{
+			name:           "case 17: ConditionChanged ignores unchanged unstructured objects",
+			predicate:      ConditionChanged(capi.ReadyCondition),
+			oldObject:      toUnstructured(t, readyFalse),
+			newObject:      toUnstructured(t, readyFalseOtherMessage),
+			expectedResult: false,
+		},
+		{
+			name:           "case 18: Objects without conditions getter are not filtered",
+			predicate:      ConditionChanged(capi.ReadyCondition),
+			oldObject:      &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test"}},
+			newObject:      &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test"}},
+			expectedResult: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Log(tc.name)
+
+			result := tc.predicate.Update(event.UpdateEvent{ObjectOld: tc.oldObject, ObjectNew: tc.newObject})
+
+			if result != tc.expectedResult {
+				t.Fatalf("expected %t, got %t", tc.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestConditionBecameOtherEvents(t *testing.T) {
+	readyTrue := conditionstest.NewCluster().WithCondition(capi.ReadyCondition, conditionstest.True).Build()
+	readyFalse := conditionstest.NewCluster().WithCondition(capi.ReadyCondition, conditionstest.False).Reason("NodesNotReady").Build()
+	p := ConditionBecame(capi.ReadyCondition, corev1.ConditionTrue)
+
+	if !p.Create(event.CreateEvent{Object: readyTrue}) {
+		t.Fatalf("expected create event for object with Ready True to be accepted")
+	}
+	if p.Create(event.CreateEvent{Object: readyFalse}) {
+		t.Fatalf("expected create event for object with Ready False to be ignored")
+	}
+	if !p.Generic(event.GenericEvent{Object: readyTrue}) {
+		t.Fatalf("expected generic event for object with Ready True to be accepted")
+	}
+	if p.Delete(event.DeleteEvent{Object: readyTrue}) {
+		t.Fatalf("expected delete event to be ignored")
+	}
+}
+
+func TestChangePredicatesAcceptOtherEvents(t *testing.T) {
+	cluster := conditionstest.NewCluster().WithCondition(capi.ReadyCondition, conditionstest.True).Build()
+
+	for _, p := range []predicate.Predicate{ConditionChanged(), ReasonChanged(capi.ReadyCondition)} {
+		if !p.Create(event.CreateEvent{Object: cluster}) || !p.Delete(event.DeleteEvent{Object: cluster}) || !p.Generic(event.GenericEvent{Object: cluster}) {
+			t.Fatalf("expected create, delete and generic events to be accepted")
+		}
+	}
+}
+
+func toUnstructured(t *testing.T, object runtime.Object) *unstructured.Unstructured {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	return &unstructured.Unstructured{Object: content}
+}